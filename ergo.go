@@ -84,7 +84,7 @@ func newDefaultError(msg string, attrs ...slog.Attr) error {
 		// Because it is not goroutine safe, the attrs must be cloned.
 		// see: https://go.dev/play/p/Q5a9oG_Uv2i
 		attrs:      slices.Clone(attrs),
-		stacktrace: caller.New(2),
+		stacktrace: captureStack(2),
 	}
 }
 
@@ -117,8 +117,8 @@ func Wrap(parent error, msg string, attrs ...slog.Attr) error {
 		attrs:  slices.Clone(attrs),
 	}
 
-	if st := StackTraceOf(parent); st == nil {
-		err.stacktrace = caller.New(1)
+	if st := RawStackTraceOf(parent); st == nil {
+		err.stacktrace = captureStack(1)
 	}
 
 	return err
@@ -159,8 +159,15 @@ func attrsAll(err error, done map[string]struct{}) iter.Seq[slog.Attr] {
 }
 
 // StackTraceOf returns stacktrace of the given error.
+// The filters set by [SetFrameFilter] and the maximum depth set by [SetMaxStackDepth] are applied to the stacktrace.
 // If err does not have stacktrace, StackTraceOf returns nil.
 func StackTraceOf(err error) caller.StackTrace {
+	return filterStack(RawStackTraceOf(err))
+}
+
+// RawStackTraceOf returns stacktrace of the given error without applying the filters.
+// If err does not have stacktrace, RawStackTraceOf returns nil.
+func RawStackTraceOf(err error) caller.StackTrace {
 	var defaultError *defaultError
 	if !errors.As(err, &defaultError) {
 		return nil
@@ -170,5 +177,5 @@ func StackTraceOf(err error) caller.StackTrace {
 		return st
 	}
 
-	return StackTraceOf(defaultError.parent)
+	return RawStackTraceOf(defaultError.parent)
 }
@@ -204,3 +204,7 @@ func attrs(t *testing.T, args ...any) []slog.Attr {
 
 	return attrs
 }
+
+func options[T any](opts ...T) []T {
+	return opts
+}
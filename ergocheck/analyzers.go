@@ -0,0 +1,199 @@
+package ergocheck
+
+import (
+	"reflect"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Analyzers are the analyzers of each check of ergocheck.
+// It includes the common analyzer named "ergocheck" which has the flags shared by the other analyzers
+// and reports malformed ignore directives.
+// [Analyzer] runs all of them as a single analyzer.
+var Analyzers = []*analysis.Analyzer{
+	sharedAnalyzer,
+	DeprecatedFuncAnalyzer,
+	FormatStringAnalyzer,
+	NilErrAnalyzer,
+	VarInitAnalyzer,
+	NewCodeAnalyzer,
+	ErrComparisonAnalyzer,
+	AttrErrAnalyzer,
+	RedundantWrapAnalyzer,
+	MessageStyleAnalyzer,
+	PanicErrAnalyzer,
+	LogReturnAnalyzer,
+	SentinelAnalyzer,
+	CodePkgAnalyzer,
+	ErrAssertAnalyzer,
+}
+
+// WithDirectives returns a copy of the analyzer of a check which also reports malformed ignore directives.
+// It is for drivers such as golangci-lint which do not report the diagnostics of the common analyzer in [Analyzers]
+// because the common analyzer is run only as a requirement of the other analyzers.
+func WithDirectives(a *analysis.Analyzer) *analysis.Analyzer {
+	copied := *a
+	copied.Run = func(pass *analysis.Pass) (any, error) {
+		if s, ok := pass.ResultOf[sharedAnalyzer].(*shared); ok {
+			for _, diag := range s.malformed {
+				pass.Report(diag)
+			}
+		}
+		return a.Run(pass)
+	}
+	return &copied
+}
+
+// DeprecatedFuncAnalyzer detects calling errors.New and fmt.Errorf.
+var DeprecatedFuncAnalyzer = newCheckAnalyzer(nameDeprecatedFunc, "deprecatedfunc detects calling errors.New and fmt.Errorf which should be replaced by ergo.New and ergo.Wrap", func(r *runner) {
+	for instr := range r.instrs() {
+		r.checkDeprecatedFunc(instr)
+	}
+})
+
+// FormatStringAnalyzer detects format strings in messages of ergo.New and ergo.Wrap.
+var FormatStringAnalyzer = newCheckAnalyzer(nameFormatString, "formatstring detects format strings in messages of ergo.New and ergo.Wrap", func(r *runner) {
+	for instr := range r.instrs() {
+		r.checkFormatString(instr)
+	}
+})
+
+// NilErrAnalyzer detects nil errors passed to ergo.Wrap and ergo.WithCode.
+// It exports [NilResultFact] and [WrapParamFact] to detect them across functions and packages.
+var NilErrAnalyzer = &analysis.Analyzer{
+	Name: nameNilErr,
+	Doc:  "nilerr detects nil errors passed to ergo.Wrap and ergo.WithCode",
+	Run: func(pass *analysis.Pass) (any, error) {
+		s, ok := pass.ResultOf[sharedAnalyzer].(*shared)
+		if !ok {
+			return []analysis.Diagnostic(nil), nil
+		}
+
+		// the facts are needed even if the package is not a target
+		facts := newNilErrFacts(pass, s)
+
+		if !s.target || !s.config.Enabled(nameNilErr) || s.config.Allowed(nameNilErr, s.pkgdir) {
+			// skip
+			return []analysis.Diagnostic(nil), nil
+		}
+
+		r := &runner{shared: s, name: nameNilErr, pass: pass}
+		for instr := range r.instrs() {
+			r.checkNilErr(instr, facts)
+		}
+
+		return r.diagnostics, nil
+	},
+	Requires: []*analysis.Analyzer{
+		sharedAnalyzer,
+	},
+	ResultType: reflect.TypeOf([]analysis.Diagnostic(nil)),
+	FactTypes: []analysis.Fact{
+		new(NilResultFact),
+		new(WrapParamFact),
+	},
+}
+
+// VarInitAnalyzer detects calling ergo.New and ergo.Wrap in package variable initializations.
+var VarInitAnalyzer = newCheckAnalyzer(nameVarInit, "varinit detects calling ergo.New and ergo.Wrap in package variable initializations", func(r *runner) {
+	r.checkVarInit()
+})
+
+// NewCodeAnalyzer detects calling ergo.NewCode outside of package variable initializations,
+// non-constant keys and duplicated keys.
+var NewCodeAnalyzer = newCheckAnalyzer(nameNewCode, "newcode detects calling ergo.NewCode outside of package variable initializations, non-constant keys and duplicated keys", func(r *runner) {
+	r.checkNewCode()
+})
+
+// ErrComparisonAnalyzer detects comparing errors with == or != instead of errors.Is
+// and comparing errors with errors created by ergo.New or ergo.Wrap.
+var ErrComparisonAnalyzer = newCheckAnalyzer(nameErrComparison, "errcomparison detects comparing errors with == or != instead of errors.Is and comparing errors with errors created by ergo.New or ergo.Wrap", func(r *runner) {
+	for instr := range r.instrs() {
+		r.checkErrComparison(instr)
+	}
+})
+
+// AttrErrAnalyzer detects errors passed as attribute values of ergo.New and ergo.Wrap.
+var AttrErrAnalyzer = newCheckAnalyzer(nameAttrErr, "attrerr detects errors passed as attribute values of ergo.New and ergo.Wrap", func(r *runner) {
+	r.checkAttrErr()
+})
+
+// RedundantWrapAnalyzer detects redundant wrapping such as ergo.Wrap with an empty message,
+// wrapping the same error twice in a function and ergo.WithCode for an error which already has a code.
+var RedundantWrapAnalyzer = newCheckAnalyzer(nameRedundantWrap, "redundantwrap detects ergo.Wrap with an empty message and no attributes, wrapping an error twice in a function and ergo.WithCode for an error which already has a code", func(r *runner) {
+	for instr := range r.instrs() {
+		r.checkRedundantWrap(instr)
+	}
+})
+
+// MessageStyleAnalyzer detects messages of ergo.New and ergo.Wrap which do not follow the message style
+// and messages of ergo.Wrap which stutter with the messages of the wrapped errors.
+var MessageStyleAnalyzer = newCheckAnalyzer(nameMessageStyle, "messagestyle detects messages of ergo.New and ergo.Wrap which do not follow the message style and messages of ergo.Wrap which stutter with the messages of the wrapped errors", func(r *runner) {
+	for instr := range r.instrs() {
+		r.checkMessageStyle(instr)
+	}
+})
+
+// PanicErrAnalyzer detects panics with error values in non-main packages.
+var PanicErrAnalyzer = newCheckAnalyzer(namePanicErr, "panicerr detects panics with error values in non-main packages, the errors should be returned", func(r *runner) {
+	for instr := range r.instrs() {
+		r.checkPanicErr(instr)
+	}
+})
+
+// LogReturnAnalyzer detects errors which are both logged and returned in a function.
+var LogReturnAnalyzer = newCheckAnalyzer(nameLogReturn, "logreturn detects errors which are both logged with log/slog or log and returned in a function, which causes duplicated logs", func(r *runner) {
+	for _, fn := range r.funcs {
+		r.checkLogReturn(fn)
+	}
+})
+
+// SentinelAnalyzer detects ergo.NewSentinel outside of package variable initializations,
+// sentinel errors which are not named with the prefix Err, reassigned sentinel errors
+// and sentinel errors created by errors.New.
+var SentinelAnalyzer = newCheckAnalyzer(nameSentinel, "sentinel detects ergo.NewSentinel outside of package variable initializations, sentinel errors which are not named with the prefix Err, reassigned exported sentinel errors and sentinel errors created by errors.New", func(r *runner) {
+	r.checkSentinel()
+})
+
+// CodePkgAnalyzer detects ergo.WithCode with codes declared in packages which are not allowed.
+var CodePkgAnalyzer = newCheckAnalyzer(nameCodePkg, "codepkg detects ergo.WithCode with codes declared in packages other than the package itself and the allowed packages", func(r *runner) {
+	for instr := range r.instrs() {
+		r.checkCodePkg(instr)
+	}
+})
+
+// ErrAssertAnalyzer detects type assertions and type switches on errors instead of errors.As
+// and comparing messages of errors with string literals.
+var ErrAssertAnalyzer = newCheckAnalyzer(nameErrAssert, "errassert detects type assertions and type switches on errors which should be replaced by errors.As and comparing messages of errors with string literals", func(r *runner) {
+	r.checkErrAssert()
+})
+
+// newCheckAnalyzer creates an analyzer of the check which requires [sharedAnalyzer].
+// The result of the analyzer is the reported diagnostics which are reported again by [Analyzer].
+// The check is run only if the package is a target and the check is enabled by the configuration.
+func newCheckAnalyzer(name, doc string, check func(r *runner)) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name: name,
+		Doc:  doc,
+		Run: func(pass *analysis.Pass) (any, error) {
+			s, ok := pass.ResultOf[sharedAnalyzer].(*shared)
+			if !ok || !s.target {
+				// skip
+				return []analysis.Diagnostic(nil), nil
+			}
+
+			if !s.config.Enabled(name) || s.config.Allowed(name, s.pkgdir) {
+				// skip
+				return []analysis.Diagnostic(nil), nil
+			}
+
+			r := &runner{shared: s, name: name, pass: pass}
+			check(r)
+			return r.diagnostics, nil
+		},
+		Requires: []*analysis.Analyzer{
+			sharedAnalyzer,
+		},
+		ResultType: reflect.TypeOf([]analysis.Diagnostic(nil)),
+	}
+}
@@ -0,0 +1,197 @@
+package ergocheck
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// called reports whether the instruction calls the function.
+// Unlike analysisutil.Called, the callee is resolved via function variables and generic wrappers
+// by [shared.calleeOf].
+func (r *runner) called(instr ssa.Instruction, f *types.Func) bool {
+	return f != nil && r.calleeOf(instr) == f
+}
+
+// calleeOf returns the function which is called by the instruction.
+// The callee is resolved as follows:
+//
+//	f := ergo.New         // local function variables
+//	var newErr = ergo.New // package function variables which are never reassigned
+//	f := logger.Error     // method values
+//	newError[T]("msg")    // generic functions which forward their parameters to another function
+func (s *shared) calleeOf(instr ssa.Instruction) *types.Func {
+	call, ok := instr.(ssa.CallInstruction)
+	if !ok || call.Common().IsInvoke() {
+		return nil
+	}
+	return s.funcOf(call.Common().Value, make(map[ssa.Value]bool))
+}
+
+func (s *shared) funcOf(v ssa.Value, done map[ssa.Value]bool) *types.Func {
+	if done[v] {
+		return nil
+	}
+	done[v] = true
+
+	switch v := v.(type) {
+	case *ssa.Function:
+		fn := v
+		if fn.Origin() != nil {
+			fn = fn.Origin()
+		}
+
+		if fn.TypeParams().Len() > 0 {
+			if f := s.forwardedFunc(fn, done); f != nil {
+				return f
+			}
+		}
+
+		// the object of a bound method wrapper is the method
+		f, _ := fn.Object().(*types.Func)
+		return f
+	case *ssa.MakeClosure:
+		// method values
+		return s.funcOf(v.Fn, done)
+	case *ssa.UnOp:
+		global, ok := v.X.(*ssa.Global)
+		if !ok || v.Op != token.MUL {
+			return nil
+		}
+
+		// the package variable must not be reassigned
+		if obj, ok := global.Object().(*types.Var); !ok || s.funcVars[obj] == nil {
+			return nil
+		}
+
+		if init, ok := s.globalInits[global]; ok {
+			return s.funcOf(init, done)
+		}
+	}
+
+	return nil
+}
+
+// forwardedFunc returns the function to which the generic function forwards its parameters such as:
+//
+//	func newError[T any](msg string, args ...any) error {
+//		return ergo.New(msg, args...)
+//	}
+//
+// The arguments of a call of the generic function can be treated as the arguments of the forwarded function.
+func (s *shared) forwardedFunc(fn *ssa.Function, done map[ssa.Value]bool) *types.Func {
+	var forwarded *types.Func
+	for _, b := range fn.Blocks {
+		ret, ok := b.Instrs[len(b.Instrs)-1].(*ssa.Return)
+		if !ok {
+			continue
+		}
+
+		if len(ret.Results) != 1 {
+			return nil
+		}
+
+		call, ok := ret.Results[0].(*ssa.Call)
+		if !ok || call.Call.IsInvoke() || len(call.Call.Args) != len(fn.Params) {
+			return nil
+		}
+
+		for i, arg := range call.Call.Args {
+			switch a := arg.(type) {
+			case *ssa.MakeInterface:
+				arg = a.X
+			case *ssa.ChangeInterface:
+				arg = a.X
+			case *ssa.ChangeType:
+				arg = a.X
+			}
+
+			if arg != fn.Params[i] {
+				return nil
+			}
+		}
+
+		f := s.funcOf(call.Call.Value, done)
+		if f == nil || (forwarded != nil && forwarded != f) {
+			return nil
+		}
+		forwarded = f
+	}
+
+	return forwarded
+}
+
+// funcVars returns the initial values of the variables which are assigned only once such as:
+//
+//	var newErr = ergo.New
+//	f := ergo.New
+//
+// They are used to resolve the callees in the syntax trees.
+func funcVars(pass *analysis.Pass) map[*types.Var]ast.Expr {
+	var (
+		vars    = make(map[*types.Var]ast.Expr)
+		assigns = make(map[*types.Var]int)
+		escaped = make(map[*types.Var]bool)
+	)
+
+	varOf := func(x ast.Expr) *types.Var {
+		id, ok := ast.Unparen(x).(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		v, _ := pass.TypesInfo.ObjectOf(id).(*types.Var)
+		return v
+	}
+
+	assign := func(lhs, rhs ast.Expr) {
+		v := varOf(lhs)
+		if v == nil {
+			return
+		}
+
+		assigns[v]++
+		if _, ok := v.Type().Underlying().(*types.Signature); ok && rhs != nil {
+			vars[v] = rhs
+		}
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				for i, lhs := range n.Lhs {
+					var rhs ast.Expr
+					if len(n.Lhs) == len(n.Rhs) {
+						rhs = n.Rhs[i]
+					}
+					assign(lhs, rhs)
+				}
+			case *ast.ValueSpec:
+				for i, name := range n.Names {
+					var rhs ast.Expr
+					if len(n.Names) == len(n.Values) {
+						rhs = n.Values[i]
+					}
+					assign(name, rhs)
+				}
+			case *ast.UnaryExpr:
+				// the variable may be assigned via its address
+				if v := varOf(n.X); v != nil && n.Op == token.AND {
+					escaped[v] = true
+				}
+			}
+			return true
+		})
+	}
+
+	for v := range vars {
+		if assigns[v] != 1 || escaped[v] {
+			delete(vars, v)
+		}
+	}
+
+	return vars
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+
+	"github.com/newmo-oss/ergo"
+)
+
+// baselineVersion is the version of the format of baseline files.
+const baselineVersion = 1
+
+// baseline is the findings which have been recorded to be ignored.
+// Each finding is keyed by its package, check and fingerprint,
+// thus it is still ignored after lines are added or removed around it.
+type baseline struct {
+	Version  int              `json:"version"`
+	Findings []*baselineEntry `json:"findings"`
+}
+
+type baselineEntry struct {
+	Package     string `json:"package"`
+	Check       string `json:"check"`
+	Fingerprint string `json:"fingerprint"`
+	// Message is only for readers of the baseline file.
+	Message string `json:"message"`
+}
+
+type baselineKey struct {
+	pkg         string
+	check       string
+	fingerprint string
+}
+
+func readBaseline(path string) (*baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ergo.Wrap(err, "failed to read the baseline file", slog.String("path", path))
+	}
+
+	var b baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, ergo.Wrap(err, "failed to decode the baseline file", slog.String("path", path))
+	}
+
+	if b.Version != baselineVersion {
+		return nil, ergo.New("unsupported version of the baseline file", slog.String("path", path), slog.Int("version", b.Version))
+	}
+
+	return &b, nil
+}
+
+func writeBaseline(path string, findings []*finding) error {
+	b := &baseline{
+		Version:  baselineVersion,
+		Findings: make([]*baselineEntry, len(findings)),
+	}
+
+	for i, f := range findings {
+		b.Findings[i] = &baselineEntry{
+			Package:     f.Package,
+			Check:       f.Check,
+			Fingerprint: f.Fingerprint,
+			Message:     f.Message,
+		}
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return ergo.Wrap(err, "failed to encode the baseline file")
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return ergo.Wrap(err, "failed to write the baseline file", slog.String("path", path))
+	}
+
+	return nil
+}
+
+// filter returns the findings which are not recorded in the baseline.
+// An entry of the baseline matches only one finding,
+// thus a new finding which has the same fingerprint as an existing one is not ignored.
+func (b *baseline) filter(findings []*finding) []*finding {
+	counts := make(map[baselineKey]int)
+	for _, e := range b.Findings {
+		counts[baselineKey{e.Package, e.Check, e.Fingerprint}]++
+	}
+
+	var newFindings []*finding
+	for _, f := range findings {
+		key := baselineKey{f.Package, f.Check, f.Fingerprint}
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		newFindings = append(newFindings, f)
+	}
+
+	return newFindings
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/newmo-oss/ergo/ergocheck"
+)
+
+// exit codes of the standalone driver.
+// exitFindings is the same as the exit code of multichecker when it reports diagnostics.
+// The findings whose severity is warning or info do not fail.
+const (
+	exitOK       = 0
+	exitError    = 1
+	exitFindings = 3
+)
+
+// finding is a diagnostic of a check.
+type finding struct {
+	Package  string             `json:"package"`
+	Check    string             `json:"check"`
+	Severity ergocheck.Severity `json:"severity"`
+	Message  string             `json:"message"`
+	File     string             `json:"file"`
+	Line     int                `json:"line"`
+	Column   int                `json:"column"`
+	// Fingerprint identifies the finding without its position.
+	// It does not change when lines are added or removed around the finding.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// driver is the standalone driver which reports findings with a baseline file.
+type driver struct {
+	dir           string
+	format        string
+	output        string
+	baseline      string
+	writeBaseline bool
+	stdout        io.Writer
+	stderr        io.Writer
+}
+
+// run runs the standalone driver in the directory and returns the exit code.
+func run(args []string, dir string, stdout, stderr io.Writer) int {
+	d := &driver{dir: dir, stdout: stdout, stderr: stderr}
+
+	flags := flag.NewFlagSet("ergocheck", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&d.format, "format", "text", "output format: text, json or sarif")
+	flags.StringVar(&d.output, "output", "", "output file (default: stdout)")
+	flags.StringVar(&d.baseline, "baseline", "", "baseline file, findings recorded in it do not fail")
+	flags.BoolVar(&d.writeBaseline, "write-baseline", false, "record the current findings in the baseline file instead of reporting them")
+
+	// the flags of the analyzers are prefixed by their names as well as multichecker
+	for _, a := range ergocheck.Analyzers {
+		a.Flags.VisitAll(func(f *flag.Flag) {
+			flags.Var(f.Value, a.Name+"."+f.Name, f.Usage)
+		})
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitError
+	}
+
+	n, err := d.run(flags.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, "ergocheck:", err)
+		return exitError
+	}
+
+	if n > 0 {
+		return exitFindings
+	}
+
+	return exitOK
+}
+
+// run analyzes the packages and reports the findings which are not recorded in the baseline file.
+// It returns the number of the reported findings whose severity is error.
+func (d *driver) run(patterns []string) (int, error) {
+	if !slices.Contains([]string{"text", "json", "sarif"}, d.format) {
+		return 0, ergo.New("unknown output format", slog.String("format", d.format))
+	}
+
+	if d.writeBaseline && d.baseline == "" {
+		return 0, ergo.New("-write-baseline requires -baseline")
+	}
+
+	findings, err := d.analyze(patterns)
+	if err != nil {
+		return 0, err
+	}
+
+	if d.writeBaseline {
+		if err := writeBaseline(d.baseline, findings); err != nil {
+			return 0, err
+		}
+		fmt.Fprintf(d.stderr, "ergocheck: %d findings are recorded in %s\n", len(findings), d.baseline)
+		return 0, nil
+	}
+
+	if d.baseline != "" {
+		b, err := readBaseline(d.baseline)
+		if err != nil {
+			return 0, err
+		}
+
+		all := len(findings)
+		findings = b.filter(findings)
+		if suppressed := all - len(findings); suppressed > 0 {
+			fmt.Fprintf(d.stderr, "ergocheck: %d findings are suppressed by %s\n", suppressed, d.baseline)
+		}
+	}
+
+	if err := d.writeFindings(findings); err != nil {
+		return 0, err
+	}
+
+	var n int
+	for _, f := range findings {
+		if f.Severity == ergocheck.SeverityError {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// writeFindings writes the findings in the format to the output file or the standard output.
+func (d *driver) writeFindings(findings []*finding) error {
+	if d.output == "" {
+		return d.write(d.stdout, findings)
+	}
+
+	f, err := os.Create(d.output)
+	if err != nil {
+		return ergo.Wrap(err, "failed to create the output file", slog.String("path", d.output))
+	}
+
+	if err := d.write(f, findings); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	// the findings may not be written until the file is closed
+	if err := f.Close(); err != nil {
+		return ergo.Wrap(err, "failed to close the output file", slog.String("path", d.output))
+	}
+
+	return nil
+}
+
+func (d *driver) write(w io.Writer, findings []*finding) error {
+	switch d.format {
+	case "json":
+		return writeJSON(w, findings)
+	case "sarif":
+		return writeSARIF(w, findings)
+	default:
+		return writeText(w, findings)
+	}
+}
+
+// analyze runs the analyzers on the packages and returns the findings in the root packages.
+func (d *driver) analyze(patterns []string) ([]*finding, error) {
+	config := &packages.Config{
+		Mode: packages.LoadAllSyntax,
+		Dir:  d.dir,
+	}
+
+	pkgs, err := packages.Load(config, patterns...)
+	if err != nil {
+		return nil, ergo.Wrap(err, "failed to load packages", slog.Any("patterns", patterns))
+	}
+
+	var loadErrs int
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, err := range pkg.Errors {
+			fmt.Fprintln(d.stderr, err)
+			loadErrs++
+		}
+	})
+	if loadErrs > 0 {
+		return nil, ergo.New("failed to load packages", slog.Any("patterns", patterns), slog.Int("errors", loadErrs))
+	}
+
+	graph, err := checker.Analyze(ergocheck.Analyzers, pkgs, nil)
+	if err != nil {
+		return nil, ergo.Wrap(err, "failed to analyze packages")
+	}
+
+	lines := newSourceLines()
+	var findings []*finding
+	for act := range graph.All() {
+		if !act.IsRoot {
+			continue
+		}
+
+		if act.Err != nil {
+			return nil, ergo.Wrap(act.Err, "failed to run the analyzer", slog.String("analyzer", act.Analyzer.Name), slog.String("package", act.Package.PkgPath))
+		}
+
+		for _, diag := range act.Diagnostics {
+			pos := act.Package.Fset.Position(diag.Pos)
+			findings = append(findings, d.newFinding(act.Package.PkgPath, act.Analyzer.Name, diag.Category, diag.Message, pos, lines))
+		}
+	}
+
+	slices.SortFunc(findings, func(x, y *finding) int {
+		return cmp.Or(
+			strings.Compare(x.File, y.File),
+			cmp.Compare(x.Line, y.Line),
+			cmp.Compare(x.Column, y.Column),
+			strings.Compare(x.Check, y.Check),
+			strings.Compare(x.Message, y.Message),
+		)
+	})
+
+	return findings, nil
+}
+
+func (d *driver) newFinding(pkgpath, analyzer, category, msg string, pos token.Position, lines *sourceLines) *finding {
+	check := category
+	if check == "" {
+		check = analyzer
+	}
+
+	// the severity is prefixed to the message by the analyzers
+	severity := ergocheck.SeverityError
+	for _, s := range []ergocheck.Severity{ergocheck.SeverityWarning, ergocheck.SeverityInfo} {
+		if rest, ok := strings.CutPrefix(msg, string(s)+": "); ok {
+			severity, msg = s, rest
+			break
+		}
+	}
+
+	file := pos.Filename
+	if abs, err := filepath.Abs(d.dir); err == nil {
+		if rel, err := filepath.Rel(abs, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+
+	return &finding{
+		Package:     pkgpath,
+		Check:       check,
+		Severity:    severity,
+		Message:     msg,
+		File:        filepath.ToSlash(file),
+		Line:        pos.Line,
+		Column:      pos.Column,
+		Fingerprint: fingerprint(check, msg, lines.line(pos.Filename, pos.Line)),
+	}
+}
+
+// fingerprint returns a position independent fingerprint of the finding.
+// It is computed from the check, the message and the source code of the line,
+// thus it does not change when the line moves.
+func fingerprint(check, msg, line string) string {
+	h := sha256.New()
+	for _, s := range []string{check, msg, strings.TrimSpace(line)} {
+		io.WriteString(h, s)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// sourceLines caches the lines of source files.
+type sourceLines struct {
+	files map[string][]string
+}
+
+func newSourceLines() *sourceLines {
+	return &sourceLines{files: make(map[string][]string)}
+}
+
+// line returns the source code of the line.
+// It returns an empty string if the file cannot be read.
+func (lines *sourceLines) line(filename string, line int) string {
+	if _, ok := lines.files[filename]; !ok {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			lines.files[filename] = nil
+		} else {
+			lines.files[filename] = strings.Split(string(data), "\n")
+		}
+	}
+
+	if line < 1 || line > len(lines.files[filename]) {
+		return ""
+	}
+	return lines.files[filename][line-1]
+}
+
+func writeText(w io.Writer, findings []*finding) error {
+	for _, f := range findings {
+		msg := f.Message
+		if f.Severity != ergocheck.SeverityError {
+			msg = string(f.Severity) + ": " + msg
+		}
+
+		if _, err := fmt.Fprintf(w, "%s:%d:%d: %s (%s)\n", f.File, f.Line, f.Column, msg, f.Check); err != nil {
+			return ergo.Wrap(err, "failed to write findings")
+		}
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, findings []*finding) error {
+	if findings == nil {
+		findings = []*finding{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(map[string]any{"findings": findings}); err != nil {
+		return ergo.Wrap(err, "failed to write findings as JSON")
+	}
+	return nil
+}
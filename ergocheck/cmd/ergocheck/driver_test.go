@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		dir       string
+		format    string
+		wantCode  int
+		wantCount int
+	}{
+		"json":    {"a", "json", exitFindings, 2},
+		"sarif":   {"a", "sarif", exitFindings, 2},
+		"warning": {"c", "json", exitOK, 2},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+			code := run([]string{"-format", tt.format, "./..."}, filepath.Join("testdata", tt.dir), &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Fatalf("exit code does not match: (got, want) = (%d, %d): %s", code, tt.wantCode, &stderr)
+			}
+
+			var got int
+			switch tt.format {
+			case "json":
+				var out struct{ Findings []*finding }
+				if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+					t.Fatal("unexpected error:", err)
+				}
+				got = len(out.Findings)
+			case "sarif":
+				var out sarifLog
+				if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+					t.Fatal("unexpected error:", err)
+				}
+				if len(out.Runs) != 1 {
+					t.Fatalf("the number of runs does not match: (got, want) = (%d, %d)", len(out.Runs), 1)
+				}
+				got = len(out.Runs[0].Results)
+			}
+
+			if got != tt.wantCount {
+				t.Errorf("the number of findings does not match: (got, want) = (%d, %d)", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestRunWithBaseline(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-baseline", path, "-write-baseline", "./..."}, filepath.Join("testdata", "a"), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("exit code does not match: (got, want) = (%d, %d): %s", code, exitOK, &stderr)
+	}
+
+	// the recorded findings are suppressed
+	stdout.Reset()
+	stderr.Reset()
+	code = run([]string{"-baseline", path, "./..."}, filepath.Join("testdata", "a"), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("exit code does not match: (got, want) = (%d, %d): %s", code, exitOK, &stderr)
+	}
+
+	// testdata/b has a new finding before the recorded findings
+	stdout.Reset()
+	stderr.Reset()
+	code = run([]string{"-baseline", path, "-format", "json", "./..."}, filepath.Join("testdata", "b"), &stdout, &stderr)
+	if code != exitFindings {
+		t.Fatalf("exit code does not match: (got, want) = (%d, %d): %s", code, exitFindings, &stderr)
+	}
+
+	var out struct{ Findings []*finding }
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var lines []int
+	for _, f := range out.Findings {
+		lines = append(lines, f.Line)
+	}
+
+	if diff := cmp.Diff([]int{7}, lines); diff != "" {
+		t.Errorf("the lines of new findings do not match (-want +got):\n%s", diff)
+	}
+}
+
+func TestBaselineFilter(t *testing.T) {
+	t.Parallel()
+
+	b := &baseline{
+		Version: baselineVersion,
+		Findings: []*baselineEntry{
+			{Package: "a", Check: "nilerr", Fingerprint: "fp1"},
+			{Package: "a", Check: "nilerr", Fingerprint: "fp2"},
+		},
+	}
+
+	findings := []*finding{
+		{Package: "a", Check: "nilerr", Fingerprint: "fp1", Line: 10},
+		{Package: "a", Check: "nilerr", Fingerprint: "fp1", Line: 20}, // same fingerprint
+		{Package: "a", Check: "nilerr", Fingerprint: "fp2", Line: 30},
+		{Package: "b", Check: "nilerr", Fingerprint: "fp2", Line: 40},  // other package
+		{Package: "a", Check: "attrerr", Fingerprint: "fp2", Line: 50}, // other check
+	}
+
+	var got []int
+	for _, f := range b.filter(findings) {
+		got = append(got, f.Line)
+	}
+
+	if diff := cmp.Diff([]int{20, 40, 50}, got); diff != "" {
+		t.Errorf("filtered findings do not match (-want +got):\n%s", diff)
+	}
+}
+
+func TestStandalone(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		args []string
+		want bool
+	}{
+		"no flags":       {[]string{"./..."}, false},
+		"analyzer flags": {[]string{"-ergocheck.packages", "foo", "./..."}, false},
+		"format":         {[]string{"-format", "json", "./..."}, true},
+		"format with =":  {[]string{"--format=sarif", "./..."}, true},
+		"after value":    {[]string{"-ergocheck.packages", "foo", "-baseline", "b.json", "./..."}, true},
+		"write-baseline": {[]string{"-write-baseline", "./..."}, true},
+		"after --":       {[]string{"--", "-format"}, false},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := standalone(tt.args); got != tt.want {
+				t.Errorf("standalone(%q) does not match: (got, want) = (%v, %v)", tt.args, got, tt.want)
+			}
+		})
+	}
+}
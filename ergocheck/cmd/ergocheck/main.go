@@ -1,10 +1,55 @@
+// Command ergocheck runs the analyzers of ergocheck.
+//
+// By default, it runs as a multichecker which is compatible with go vet:
+//
+//	$ ergocheck ./...
+//
+// When -format, -output, -baseline or -write-baseline is given,
+// it runs as a standalone driver which writes findings as text, JSON or SARIF
+// and fails only on findings which are not recorded in the baseline file:
+//
+//	$ ergocheck -baseline .ergocheck-baseline.json -write-baseline ./...
+//	$ ergocheck -baseline .ergocheck-baseline.json -format sarif -output ergocheck.sarif ./...
 package main
 
 import (
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis/multichecker"
+
 	"github.com/newmo-oss/ergo/ergocheck"
-	"golang.org/x/tools/go/analysis/singlechecker"
 )
 
 func main() {
-	singlechecker.Main(ergocheck.Analyzer)
+	if !standalone(os.Args[1:]) {
+		multichecker.Main(ergocheck.Analyzers...)
+		return
+	}
+
+	os.Exit(run(os.Args[1:], ".", os.Stdout, os.Stderr))
+}
+
+// standaloneFlags are the flags which switch to the standalone driver.
+var standaloneFlags = []string{"format", "output", "baseline", "write-baseline"}
+
+// standalone reports whether the arguments have flags of the standalone driver.
+func standalone(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		if !strings.HasPrefix(arg, "-") {
+			// a package pattern or a value of a flag
+			continue
+		}
+
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if slices.Contains(standaloneFlags, name) {
+			return true
+		}
+	}
+	return false
 }
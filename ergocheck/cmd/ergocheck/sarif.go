@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/newmo-oss/ergo/ergocheck"
+)
+
+// types of SARIF 2.1.0 which are used by ergocheck.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type (
+	sarifLog struct {
+		Schema  string      `json:"$schema"`
+		Version string      `json:"version"`
+		Runs    []*sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool    sarifTool      `json:"tool"`
+		Results []*sarifResult `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string       `json:"name"`
+		InformationURI string       `json:"informationUri"`
+		Rules          []*sarifRule `json:"rules"`
+	}
+
+	sarifRule struct {
+		ID               string       `json:"id"`
+		ShortDescription sarifMessage `json:"shortDescription"`
+	}
+
+	sarifResult struct {
+		RuleID              string            `json:"ruleId"`
+		RuleIndex           int               `json:"ruleIndex"`
+		Level               string            `json:"level"`
+		Message             sarifMessage      `json:"message"`
+		Locations           []*sarifLocation  `json:"locations"`
+		PartialFingerprints map[string]string `json:"partialFingerprints"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           sarifRegion           `json:"region"`
+	}
+
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+	}
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	// sarifFingerprintKey is the key of partialFingerprints which has the fingerprint of a finding.
+	sarifFingerprintKey = "ergocheck/v1"
+)
+
+func writeSARIF(w io.Writer, findings []*finding) error {
+	driver := sarifDriver{
+		Name:           "ergocheck",
+		InformationURI: "https://github.com/newmo-oss/ergo/tree/main/ergocheck",
+	}
+
+	ruleIndexes := make(map[string]int)
+	for _, a := range ergocheck.Analyzers {
+		doc, _, _ := strings.Cut(a.Doc, "\n")
+		ruleIndexes[a.Name] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, &sarifRule{
+			ID:               a.Name,
+			ShortDescription: sarifMessage{Text: doc},
+		})
+	}
+
+	results := make([]*sarifResult, len(findings))
+	for i, f := range findings {
+		results[i] = &sarifResult{
+			RuleID:    f.Check,
+			RuleIndex: ruleIndexes[f.Check],
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []*sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: f.File},
+					Region: sarifRegion{
+						StartLine:   f.Line,
+						StartColumn: f.Column,
+					},
+				},
+			}},
+			PartialFingerprints: map[string]string{
+				sarifFingerprintKey: f.Fingerprint,
+			},
+		}
+	}
+
+	log := &sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []*sarifRun{{
+			Tool:    sarifTool{Driver: driver},
+			Results: results,
+		}},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(log); err != nil {
+		return ergo.Wrap(err, "failed to write findings as SARIF")
+	}
+
+	return nil
+}
+
+func sarifLevel(severity ergocheck.Severity) string {
+	switch severity {
+	case ergocheck.SeverityWarning:
+		return "warning"
+	case ergocheck.SeverityInfo:
+		return "note"
+	default:
+		return "error"
+	}
+}
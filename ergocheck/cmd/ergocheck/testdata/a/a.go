@@ -0,0 +1,11 @@
+package a
+
+import "errors"
+
+func F() error {
+	return errors.New("f")
+}
+
+func G() error {
+	return errors.New("g")
+}
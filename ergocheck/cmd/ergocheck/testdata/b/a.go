@@ -0,0 +1,16 @@
+package a
+
+import "errors"
+
+// H is added before F and G.
+func H() error {
+	return errors.New("h")
+}
+
+func F() error {
+	return errors.New("f")
+}
+
+func G() error {
+	return errors.New("g")
+}
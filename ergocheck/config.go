@@ -0,0 +1,412 @@
+package ergocheck
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/token"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/newmo-oss/ergo"
+)
+
+// names of checks which are used in configuration files and ignore directives.
+const (
+	nameDeprecatedFunc = "deprecatedfunc"
+	nameFormatString   = "formatstring"
+	nameNilErr         = "nilerr"
+	nameVarInit        = "varinit"
+	nameNewCode        = "newcode"
+	nameErrComparison  = "errcomparison"
+	nameAttrErr        = "attrerr"
+	nameRedundantWrap  = "redundantwrap"
+	nameMessageStyle   = "messagestyle"
+	namePanicErr       = "panicerr"
+	nameLogReturn      = "logreturn"
+	nameSentinel       = "sentinel"
+	nameCodePkg        = "codepkg"
+	nameErrAssert      = "errassert"
+)
+
+var checkNames = []string{
+	nameDeprecatedFunc,
+	nameFormatString,
+	nameNilErr,
+	nameVarInit,
+	nameNewCode,
+	nameErrComparison,
+	nameAttrErr,
+	nameRedundantWrap,
+	nameMessageStyle,
+	namePanicErr,
+	nameLogReturn,
+	nameSentinel,
+	nameCodePkg,
+	nameErrAssert,
+}
+
+// configFileNames are names of configuration files in priority order.
+var configFileNames = []string{
+	".ergocheck.yaml",
+	".ergocheck.yml",
+	".ergocheck.json",
+}
+
+// Severity is a severity of diagnostics of a check.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Config is a configuration of ergocheck.
+// It is loaded from .ergocheck.yaml, .ergocheck.yml or .ergocheck.json.
+//
+//	checks:
+//	  deprecatedfunc:
+//	    severity: warning
+//	    allow:
+//	      - cmd/...
+//	  formatstring:
+//	    enabled: false
+type Config struct {
+	// Checks are configurations of each check keyed by the check name.
+	Checks map[string]*CheckConfig `json:"checks" yaml:"checks"`
+
+	// dir is the directory which has the configuration file.
+	dir string
+}
+
+// CheckConfig is a configuration of a check.
+type CheckConfig struct {
+	// Enabled enables or disables the check. The check is enabled by default.
+	Enabled *bool `json:"enabled" yaml:"enabled"`
+	// Severity is the severity of diagnostics. The default is "error".
+	// go vet fails on diagnostics of any severity, but the standalone driver of the ergocheck command fails only on "error".
+	Severity Severity `json:"severity" yaml:"severity"`
+	// Allow is a list of package directories in which the check is disabled.
+	// Each directory is relative to the directory which has the configuration file.
+	// A directory which ends with "/..." matches the directory and its sub directories.
+	Allow []string `json:"allow" yaml:"allow"`
+
+	// MaxLength is the maximum length of messages for the messagestyle check.
+	// The default is 80.
+	MaxLength int `json:"maxLength" yaml:"maxLength"`
+	// Prefixes are the forbidden prefixes of messages for the messagestyle check.
+	// They are compared case-insensitively. The default is "error:" and "failed:".
+	Prefixes []string `json:"prefixes" yaml:"prefixes"`
+	// CodePackages are the import paths of packages whose codes can be attached by ergo.WithCode
+	// for the codepkg check. The codes of the package itself can always be attached.
+	// An import path which starts with "./" or "../" is relative to the import path of the package,
+	// which ends with "/..." matches the package and its sub packages
+	// and which starts with ".../" matches packages whose import paths end with the rest.
+	// The default is ".../errcodes".
+	CodePackages []string `json:"codePackages" yaml:"codePackages"`
+}
+
+const defaultMaxLength = 80
+
+var defaultPrefixes = []string{"error:", "failed:"}
+
+var defaultCodePackages = []string{".../errcodes"}
+
+// LoadConfig loads a configuration file.
+// The format of the file is decided by its extension.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ergo.Wrap(err, "failed to read the configuration file", slog.String("path", path))
+	}
+
+	var config Config
+	switch filepath.Ext(path) {
+	case ".json":
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, ergo.Wrap(err, "failed to decode the configuration file as JSON", slog.String("path", path))
+		}
+	default:
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, ergo.Wrap(err, "failed to decode the configuration file as YAML", slog.String("path", path))
+		}
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, ergo.Wrap(err, "invalid configuration file", slog.String("path", path))
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, ergo.Wrap(err, "failed to get the absolute path of the configuration file", slog.String("path", path))
+	}
+	config.dir = filepath.Dir(absPath)
+
+	return &config, nil
+}
+
+// FindConfig finds the nearest configuration file from dir to the module root, which has go.mod.
+// If there is no configuration file, FindConfig returns the default configuration.
+func FindConfig(dir string) (*Config, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, ergo.Wrap(err, "failed to get the absolute path of the directory", slog.String("dir", dir))
+	}
+
+	for {
+		for _, name := range configFileNames {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return LoadConfig(path)
+			}
+		}
+
+		// module root
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return new(Config), nil
+}
+
+var configCache sync.Map // map[string]*Config
+
+func findConfigWithCache(dir string) (*Config, error) {
+	if config, ok := configCache.Load(dir); ok {
+		return config.(*Config), nil
+	}
+
+	config, err := FindConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	configCache.Store(dir, config)
+	return config, nil
+}
+
+func (config *Config) validate() error {
+	for name, check := range config.Checks {
+		if !slices.Contains(checkNames, name) {
+			return ergo.New("unknown check", slog.String("check", name))
+		}
+
+		if check == nil {
+			continue
+		}
+
+		switch check.Severity {
+		case "", SeverityError, SeverityWarning, SeverityInfo:
+		default:
+			return ergo.New("unknown severity", slog.String("check", name), slog.String("severity", string(check.Severity)))
+		}
+
+		if check.MaxLength < 0 {
+			return ergo.New("max length must not be negative", slog.String("check", name), slog.Int("maxLength", check.MaxLength))
+		}
+	}
+	return nil
+}
+
+func (config *Config) check(name string) *CheckConfig {
+	if config == nil || config.Checks[name] == nil {
+		return new(CheckConfig)
+	}
+	return config.Checks[name]
+}
+
+// Enabled returns whether the check is enabled.
+func (config *Config) Enabled(name string) bool {
+	enabled := config.check(name).Enabled
+	return enabled == nil || *enabled
+}
+
+// Severity returns the severity of the check.
+func (config *Config) Severity(name string) Severity {
+	if severity := config.check(name).Severity; severity != "" {
+		return severity
+	}
+	return SeverityError
+}
+
+// MaxLength returns the maximum length of messages for the check.
+func (config *Config) MaxLength(name string) int {
+	if maxLength := config.check(name).MaxLength; maxLength > 0 {
+		return maxLength
+	}
+	return defaultMaxLength
+}
+
+// Prefixes returns the forbidden prefixes of messages for the check.
+func (config *Config) Prefixes(name string) []string {
+	if prefixes := config.check(name).Prefixes; prefixes != nil {
+		return prefixes
+	}
+	return defaultPrefixes
+}
+
+// CodePackageAllowed returns whether the codes declared in the package can be attached in the package of pkgpath.
+func (config *Config) CodePackageAllowed(name, pkgpath, codePkgpath string) bool {
+	if pkgpath == codePkgpath {
+		return true
+	}
+
+	patterns := config.check(name).CodePackages
+	if patterns == nil {
+		patterns = defaultCodePackages
+	}
+
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "./") || strings.HasPrefix(pattern, "../") {
+			pattern = path.Join(pkgpath, pattern)
+		}
+
+		switch {
+		case pattern == "...":
+			return true
+		case strings.HasPrefix(pattern, ".../"):
+			suffix := strings.TrimPrefix(pattern, ".../")
+			if codePkgpath == suffix || strings.HasSuffix(codePkgpath, "/"+suffix) {
+				return true
+			}
+		case strings.HasSuffix(pattern, "/..."):
+			prefix := strings.TrimSuffix(pattern, "/...")
+			if codePkgpath == prefix || strings.HasPrefix(codePkgpath, prefix+"/") {
+				return true
+			}
+		case codePkgpath == pattern:
+			return true
+		}
+	}
+
+	return false
+}
+
+// Allowed returns whether the check is disabled in the package directory by the allow list.
+func (config *Config) Allowed(name, pkgdir string) bool {
+	if config == nil || config.dir == "" {
+		return false
+	}
+
+	rel, err := filepath.Rel(config.dir, pkgdir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	for _, pattern := range config.check(name).Allow {
+		pattern = strings.TrimPrefix(pattern, "./")
+		if pattern == "..." {
+			return true
+		}
+
+		if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+			if prefix == "." || rel == prefix || strings.HasPrefix(rel, prefix+"/") {
+				return true
+			}
+			continue
+		}
+
+		if rel == strings.TrimSuffix(pattern, "/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+const ignoreDirective = "//ergocheck:ignore"
+
+// ignoreDirectives are the lines which are ignored by ignore directives such as:
+//
+//	//ergocheck:ignore formatstring,nilerr the reason of ignoring
+//
+// A directive ignores the diagnostics of the checks on the same line.
+// A directive which is alone on its line also ignores the next line.
+type ignoreDirectives map[string]map[int][]string // filename -> line -> check names
+
+// parseIgnoreDirectives parses ignore directives in the files.
+// The malformed directives are passed to the report function.
+func parseIgnoreDirectives(fset *token.FileSet, files []*ast.File, report func(pos token.Pos, msg string)) ignoreDirectives {
+	directives := make(ignoreDirectives)
+	for _, file := range files {
+		var codeLines map[int]bool
+		for _, group := range file.Comments {
+			for _, comment := range group.List {
+				args, ok := strings.CutPrefix(comment.Text, ignoreDirective)
+				if !ok || (args != "" && args[0] != ' ' && args[0] != '\t') {
+					continue
+				}
+
+				names, reason := strings.TrimSpace(args), ""
+				if i := strings.IndexAny(names, " \t"); i >= 0 {
+					names, reason = names[:i], names[i+1:]
+				}
+
+				if names == "" || strings.TrimSpace(reason) == "" {
+					report(comment.Pos(), "ergocheck:ignore directive must have check names and a reason such as //ergocheck:ignore <check> <reason>")
+					continue
+				}
+
+				checks := strings.Split(names, ",")
+				if i := slices.IndexFunc(checks, func(name string) bool {
+					return !slices.Contains(checkNames, name)
+				}); i >= 0 {
+					report(comment.Pos(), "ergocheck:ignore directive has an unknown check: "+checks[i])
+					continue
+				}
+
+				pos := fset.Position(comment.Pos())
+				if directives[pos.Filename] == nil {
+					directives[pos.Filename] = make(map[int][]string)
+				}
+				lines := []int{pos.Line}
+				if codeLines == nil {
+					codeLines = linesOfCode(fset, file)
+				}
+				if !codeLines[pos.Line] {
+					lines = append(lines, pos.Line+1)
+				}
+
+				for _, line := range lines {
+					directives[pos.Filename][line] = append(directives[pos.Filename][line], checks...)
+				}
+			}
+		}
+	}
+	return directives
+}
+
+// linesOfCode returns the lines of the file which have code other than comments.
+func linesOfCode(fset *token.FileSet, file *ast.File) map[int]bool {
+	lines := make(map[int]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n.(type) {
+		case nil, *ast.CommentGroup, *ast.Comment:
+			return false
+		}
+
+		lines[fset.Position(n.Pos()).Line] = true
+		lines[fset.Position(n.End()).Line] = true
+		return true
+	})
+	return lines
+}
+
+func (directives ignoreDirectives) ignored(name string, pos token.Position) bool {
+	return slices.Contains(directives[pos.Filename][pos.Line], name)
+}
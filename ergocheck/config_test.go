@@ -0,0 +1,145 @@
+package ergocheck_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/newmo-oss/ergo/ergocheck"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		filename string
+		content  string
+
+		wantErr      bool
+		wantEnabled  bool
+		wantSeverity ergocheck.Severity
+	}{
+		"yaml":                {".ergocheck.yaml", "checks:\n  nilerr:\n    enabled: false\n    severity: warning\n", false, false, ergocheck.SeverityWarning},
+		"json":                {".ergocheck.json", `{"checks": {"nilerr": {"severity": "info"}}}`, false, true, ergocheck.SeverityInfo},
+		"empty":               {".ergocheck.yaml", "", false, true, ergocheck.SeverityError},
+		"unknown check":       {".ergocheck.yaml", "checks:\n  unknown:\n    enabled: false\n", true, false, ""},
+		"unknown severity":    {".ergocheck.yaml", "checks:\n  nilerr:\n    severity: fatal\n", true, false, ""},
+		"invalid json":        {".ergocheck.json", `{"checks":`, true, false, ""},
+		"negative max length": {".ergocheck.yaml", "checks:\n  messagestyle:\n    maxLength: -1\n", true, false, ""},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), tt.filename)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal("failed to write the configuration file:", err)
+			}
+
+			config, err := ergocheck.LoadConfig(path)
+			switch {
+			case tt.wantErr && err == nil:
+				t.Fatal("expect LoadConfig returned an error")
+			case !tt.wantErr && err != nil:
+				t.Fatal("unexpected error:", err)
+			case err != nil:
+				return
+			}
+
+			if got := config.Enabled("nilerr"); got != tt.wantEnabled {
+				t.Errorf("Enabled does not match: (got, want) = (%v, %v)", got, tt.wantEnabled)
+			}
+
+			if got := config.Severity("nilerr"); got != tt.wantSeverity {
+				t.Errorf("Severity does not match: (got, want) = (%q, %q)", got, tt.wantSeverity)
+			}
+		})
+	}
+}
+
+func TestConfigAllowed(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".ergocheck.yaml")
+	content := "checks:\n  deprecatedfunc:\n    allow:\n      - cmd/...\n      - internal/tool\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal("failed to write the configuration file:", err)
+	}
+
+	config, err := ergocheck.FindConfig(filepath.Join(dir, "internal"))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cases := map[string]struct {
+		check  string
+		pkgdir string
+		want   bool
+	}{
+		"cmd":            {"deprecatedfunc", "cmd", true},
+		"cmd/sub":        {"deprecatedfunc", "cmd/sub", true},
+		"cmdx":           {"deprecatedfunc", "cmdx", false},
+		"internal/tool":  {"deprecatedfunc", "internal/tool", true},
+		"internal/tool2": {"deprecatedfunc", "internal/tool/sub", false},
+		"root":           {"deprecatedfunc", ".", false},
+		"other check":    {"nilerr", "cmd", false},
+		"outside":        {"deprecatedfunc", "../cmd", false},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := config.Allowed(tt.check, filepath.Join(dir, tt.pkgdir)); got != tt.want {
+				t.Errorf("Allowed(%q, %q) does not match: (got, want) = (%v, %v)", tt.check, tt.pkgdir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigCodePackageAllowed(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".ergocheck.yaml")
+	content := "checks:\n  codepkg:\n    codePackages:\n      - ../errcodes\n      - example.com/shared/...\n      - example.com/codes\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal("failed to write the configuration file:", err)
+	}
+
+	config, err := ergocheck.LoadConfig(path)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cases := map[string]struct {
+		config      *ergocheck.Config
+		pkgpath     string
+		codePkgpath string
+		want        bool
+	}{
+		"own package":         {config, "example.com/svc/a", "example.com/svc/a", true},
+		"relative":            {config, "example.com/svc/a", "example.com/svc/errcodes", true},
+		"relative other":      {config, "example.com/other/a", "example.com/svc/errcodes", false},
+		"sub packages":        {config, "example.com/svc/a", "example.com/shared/codes", true},
+		"sub packages root":   {config, "example.com/svc/a", "example.com/shared", true},
+		"exact":               {config, "example.com/svc/a", "example.com/codes", true},
+		"exact prefix":        {config, "example.com/svc/a", "example.com/codes/sub", false},
+		"other package":       {config, "example.com/svc/a", "example.com/svc/b", false},
+		"default errcodes":    {nil, "example.com/svc/a", "example.com/errcodes", true},
+		"default errcodes2":   {nil, "example.com/svc/a", "example.com/svc/errcodes", true},
+		"default other":       {nil, "example.com/svc/a", "example.com/svc/myerrcodes", false},
+		"default own package": {nil, "example.com/svc/a", "example.com/svc/a", true},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.config.CodePackageAllowed("codepkg", tt.pkgpath, tt.codePkgpath); got != tt.want {
+				t.Errorf("CodePackageAllowed(%q, %q) does not match: (got, want) = (%v, %v)", tt.pkgpath, tt.codePkgpath, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,15 +1,23 @@
 package ergocheck
 
 import (
+	"bytes"
 	"fmt"
 	"go/ast"
 	"go/constant"
+	"go/format"
 	"go/token"
 	"go/types"
 	"iter"
 	"log/slog"
+	"path/filepath"
+	"reflect"
 	"regexp"
 	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/gostaticanalysis/analysisutil"
 	"github.com/gostaticanalysis/ssainspect"
@@ -20,30 +28,71 @@ import (
 	"github.com/newmo-oss/ergo"
 )
 
-const doc = `ergocheck detects misuse usage as follows
-* calling errors.New and fmt.Errorf
-* calling ergo.New in package variable initializations
+const doc = `ergocheck runs all the checks of ergocheck
+
+ergocheck reports the diagnostics of all the enabled checks and malformed //ergocheck:ignore directives.
+The checks are also provided as the analyzers of each check such as DeprecatedFuncAnalyzer.
 `
 
+// Analyzer runs all the checks of ergocheck.
+// It reports the diagnostics of the analyzers of each check and malformed ignore directives.
+// Use [Analyzers] instead to run the checks as separate analyzers such as with multichecker.
 var Analyzer = &analysis.Analyzer{
 	Name: "ergocheck",
 	Doc:  doc,
 	Run: func(pass *analysis.Pass) (any, error) {
-		return new(runner).run(pass)
+		if s, ok := pass.ResultOf[sharedAnalyzer].(*shared); ok {
+			for _, diag := range s.malformed {
+				pass.Report(diag)
+			}
+		}
+
+		for _, a := range pass.Analyzer.Requires {
+			diags, _ := pass.ResultOf[a].([]analysis.Diagnostic)
+			for _, diag := range diags {
+				pass.Report(diag)
+			}
+		}
+
+		return nil, nil
+	},
+	Requires: Analyzers,
+}
+
+const sharedDoc = `ergocheck is the common analyzer of the ergocheck analyzers
+
+ergocheck has the flags, loads the configuration file and provides the shared result
+which includes the SSA form of the package for the analyzers of each check.
+ergocheck reports only malformed //ergocheck:ignore directives by itself.
+`
+
+// sharedAnalyzer is the common analyzer which is required by the analyzers of each check.
+// It has the same name and flags as [Analyzer],
+// thus the flags such as -ergocheck.packages are available when [Analyzers] are run by multichecker.
+var sharedAnalyzer = &analysis.Analyzer{
+	Name: "ergocheck",
+	Doc:  sharedDoc,
+	Run: func(pass *analysis.Pass) (any, error) {
+		return newShared(pass)
 	},
 	Requires: []*analysis.Analyzer{
 		buildssa.Analyzer,
 	},
+	ResultType: reflect.TypeOf(new(shared)),
 }
 
 var (
 	flagPackages string
 	flagExclues  string
+	flagConfig   string
 )
 
 func init() {
-	Analyzer.Flags.StringVar(&flagPackages, "packages", "", "target pacakges import path (regexp)")
-	Analyzer.Flags.StringVar(&flagExclues, "excludes", "", "excluded pacakges import path (regexp)")
+	for _, a := range []*analysis.Analyzer{Analyzer, sharedAnalyzer} {
+		a.Flags.StringVar(&flagPackages, "packages", "", "target pacakges import path (regexp)")
+		a.Flags.StringVar(&flagExclues, "excludes", "", "excluded pacakges import path (regexp)")
+		a.Flags.StringVar(&flagConfig, "config", "", "configuration file path (default: .ergocheck.yaml, .ergocheck.yml or .ergocheck.json found from the package directory to the module root)")
+	}
 }
 
 type libFunc struct {
@@ -51,74 +100,111 @@ type libFunc struct {
 	funcname string
 }
 
-type runner struct {
-	targetPackgeRegexp   *regexp.Regexp
-	excludePackageRegexp *regexp.Regexp
-	pass                 *analysis.Pass
-	libFuncs             map[string]*types.Func
-	ssa                  *buildssa.SSA
+// shared is the result of [sharedAnalyzer] which is shared by the analyzers of each check.
+// It must not be modified after it is created because the analyzers may run in parallel.
+type shared struct {
+	// target reports whether the package is a target of the checks.
+	target      bool
+	ssa         *buildssa.SSA
+	funcs       []*ssa.Function
+	libFuncs    map[string]*types.Func
+	globalInits map[*ssa.Global]ssa.Value
+	funcVars    map[*types.Var]ast.Expr
+	config      *Config
+	pkgdir      string
+	ignores     ignoreDirectives
+	// malformed is the diagnostics of malformed ignore directives.
+	malformed []analysis.Diagnostic
 }
 
-func (r *runner) init(pass *analysis.Pass) error {
+func newShared(pass *analysis.Pass) (*shared, error) {
+	var targetPackgeRegexp, excludePackageRegexp *regexp.Regexp
 	if flagPackages != "" {
-		targetPackgeRegexp, err := regexp.Compile(flagPackages)
+		re, err := regexp.Compile(flagPackages)
 		if err != nil {
-			return ergo.Wrap(err, "failed to compile target packages import path regexp", slog.String("regexp", flagPackages))
+			return nil, ergo.Wrap(err, "failed to compile target packages import path regexp", slog.String("regexp", flagPackages))
 		}
-		r.targetPackgeRegexp = targetPackgeRegexp
+		targetPackgeRegexp = re
 	}
 
 	if flagExclues != "" {
-		excludePackageRegexp, err := regexp.Compile(flagExclues)
+		re, err := regexp.Compile(flagExclues)
 		if err != nil {
-			return ergo.Wrap(err, "failed to compile excluded packages import path regexp", slog.String("regexp", flagExclues))
+			return nil, ergo.Wrap(err, "failed to compile excluded packages import path regexp", slog.String("regexp", flagExclues))
 		}
-		r.excludePackageRegexp = excludePackageRegexp
+		excludePackageRegexp = re
 	}
 
-	r.pass = pass
+	s := new(shared)
+
+	pkgpath := pass.Pkg.Path()
+	s.target = (targetPackgeRegexp == nil || targetPackgeRegexp.MatchString(pkgpath)) &&
+		(excludePackageRegexp == nil || !excludePackageRegexp.MatchString(pkgpath))
+
 	builtSSA, ok := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
-	if ok {
-		r.ssa = builtSSA
+	if !ok {
+		// skip
+		s.target = false
+		return s, nil
+	}
+
+	// SSA is needed for facts even if the package is not a target
+	s.ssa = builtSSA
+	s.funcs = srcFuncs(builtSSA)
+	s.globalInits = globalInits(builtSSA)
+	s.funcVars = funcVars(pass)
+
+	if s.target {
+		if len(pass.Files) > 0 {
+			s.pkgdir = filepath.Dir(pass.Fset.File(pass.Files[0].Pos()).Name())
+		}
+
+		switch {
+		case flagConfig != "":
+			config, err := LoadConfig(flagConfig)
+			if err != nil {
+				return nil, err
+			}
+			s.config = config
+		case s.pkgdir != "":
+			config, err := findConfigWithCache(s.pkgdir)
+			if err != nil {
+				return nil, err
+			}
+			s.config = config
+		}
+
+		s.ignores = parseIgnoreDirectives(pass.Fset, pass.Files, func(pos token.Pos, msg string) {
+			diag := analysis.Diagnostic{Pos: pos, Message: msg}
+			s.malformed = append(s.malformed, diag)
+			pass.Report(diag)
+		})
 	}
 
-	r.libFuncs = r.getFuncs([]libFunc{
+	s.libFuncs = getFuncs(pass, []libFunc{
 		{pkg: "errors", funcname: "New"},
 		{pkg: "fmt", funcname: "Errorf"},
 		{pkg: "github.com/newmo-oss/ergo", funcname: "New"},
 		{pkg: "github.com/newmo-oss/ergo", funcname: "Wrap"},
 		{pkg: "github.com/newmo-oss/ergo", funcname: "WithCode"},
+		{pkg: "github.com/newmo-oss/ergo", funcname: "NewCode"},
+		{pkg: "github.com/newmo-oss/ergo", funcname: "NewSentinel"},
+		{pkg: "errors", funcname: "Is"},
+		{pkg: "errors", funcname: "Unwrap"},
+		{pkg: "log/slog", funcname: "Any"},
+		{pkg: "log/slog", funcname: "String"},
 	})
 
-	return nil
-}
-
-func (r *runner) isTargetPkg(pkgpath string) bool {
-	return (r.targetPackgeRegexp == nil || r.targetPackgeRegexp.MatchString(pkgpath)) &&
-		(r.excludePackageRegexp == nil || !r.excludePackageRegexp.MatchString(pkgpath))
+	return s, nil
 }
 
-func (r *runner) run(pass *analysis.Pass) (any, error) {
-	if err := r.init(pass); err != nil {
-		return nil, err
-	}
-
-	if r.ssa == nil {
-		// skip
-		return nil, nil
-	}
-
-	pkgpath := pass.Pkg.Path()
-	if !r.isTargetPkg(pkgpath) {
-		// skip
-		return nil, nil
-	}
-
-	funcs := slices.Clone(r.ssa.SrcFuncs)
+// srcFuncs returns the source functions of the package including package variable initializations.
+func srcFuncs(builtSSA *buildssa.SSA) []*ssa.Function {
+	funcs := slices.Clone(builtSSA.SrcFuncs)
 
 	// Add dummy functions that correspond to variable initialization,
 	// because SrcFunc does not have it.
-	for _, m := range r.ssa.Pkg.Members {
+	for _, m := range builtSSA.Pkg.Members {
 		f, ok := m.(*ssa.Function)
 		// exclude package functions and methods
 		if ok && !slices.Contains(funcs, f) {
@@ -139,21 +225,79 @@ func (r *runner) run(pass *analysis.Pass) (any, error) {
 		}
 	}
 
-	for cur := range ssainspect.All(funcs) {
-		r.checkDeprecatedFunc(cur.Instr)
-		r.checkFormatString(cur.Instr)
-		r.checkNilErr(cur.Instr)
+	return funcs
+}
+
+// globalInits returns the initial values of the package variables which are declared in the package.
+func globalInits(builtSSA *buildssa.SSA) map[*ssa.Global]ssa.Value {
+	inits := make(map[*ssa.Global]ssa.Value)
+	init := builtSSA.Pkg.Func("init")
+	if init == nil {
+		return inits
+	}
+
+	for _, b := range init.Blocks {
+		for _, instr := range b.Instrs {
+			store, ok := instr.(*ssa.Store)
+			if !ok {
+				continue
+			}
+
+			if g, ok := store.Addr.(*ssa.Global); ok {
+				inits[g] = store.Val
+			}
+		}
+	}
+
+	return inits
+}
+
+// runner runs a check with the shared result of [sharedAnalyzer].
+type runner struct {
+	*shared
+	name string
+	pass *analysis.Pass
+	// diagnostics is the reported diagnostics which is the result of the analyzer of the check.
+	diagnostics []analysis.Diagnostic
+}
+
+func (r *runner) reportf(pos token.Pos, format string, args ...any) {
+	r.report(analysis.Diagnostic{
+		Pos:     pos,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// report reports the diagnostic of the check if the check is not ignored on the line.
+func (r *runner) report(diag analysis.Diagnostic) {
+	if r.ignores.ignored(r.name, r.pass.Fset.Position(diag.Pos)) {
+		return
+	}
+
+	diag.Category = r.name
+	if severity := r.config.Severity(r.name); severity != SeverityError {
+		diag.Message = string(severity) + ": " + diag.Message
 	}
 
-	r.checkVarInit()
+	r.diagnostics = append(r.diagnostics, diag)
+	r.pass.Report(diag)
+}
 
-	return nil, nil
+// instrs returns an iterator over all instructions of the functions of the package.
+func (r *runner) instrs() iter.Seq[ssa.Instruction] {
+	return func(yield func(ssa.Instruction) bool) {
+		for cur := range ssainspect.All(r.funcs) {
+			if !yield(cur.Instr) {
+				return
+			}
+		}
+	}
 }
 
-func (r *runner) getFuncs(libFuncs []libFunc) map[string]*types.Func {
+func getFuncs(pass *analysis.Pass, libFuncs []libFunc) map[string]*types.Func {
 	m := make(map[string]*types.Func)
 	for _, libFunc := range libFuncs {
-		f, ok := analysisutil.ObjectOf(r.pass, libFunc.pkg, libFunc.funcname).(*types.Func)
+		f, ok := analysisutil.ObjectOf(pass, libFunc.pkg, libFunc.funcname).(*types.Func)
 		if ok {
 			m[libFunc.pkg+"."+libFunc.funcname] = f
 		}
@@ -176,8 +320,8 @@ func (r *runner) checkDeprecatedFunc(instr ssa.Instruction) {
 		if deprecated.obj == nil {
 			continue
 		}
-		if analysisutil.Called(instr, nil, deprecated.obj) {
-			r.pass.Reportf(instr.Pos(), "%s must not be used in the %s package, it should be replaced by %s", deprecated.obj.FullName(), r.pass.Pkg.Path(), deprecated.suggest)
+		if r.called(instr, deprecated.obj) {
+			r.reportf(instr.Pos(), "%s must not be used in the %s package, it should be replaced by %s", deprecated.obj.FullName(), r.pass.Pkg.Path(), deprecated.suggest)
 		}
 	}
 }
@@ -200,7 +344,7 @@ func (r *runner) checkFormatString(instr ssa.Instruction) {
 			continue
 		}
 
-		if !analysisutil.Called(instr, nil, f.obj) {
+		if !r.called(instr, f.obj) {
 			continue
 		}
 
@@ -220,7 +364,7 @@ func (r *runner) checkFormatString(instr ssa.Instruction) {
 
 		msg := constant.StringVal(msgarg.Value)
 		if formatRegexp.MatchString(msg) {
-			r.pass.Reportf(instr.Pos(), `the message of %s must not be format string such as "xxxx %%s": %q`, f.obj.FullName(), msg)
+			r.reportf(instr.Pos(), `the message of %s must not be format string such as "xxxx %%s": %q`, f.obj.FullName(), msg)
 		}
 	}
 }
@@ -230,7 +374,12 @@ type wrapFunc struct {
 	arg int
 }
 
-func (r *runner) checkNilErr(instr ssa.Instruction) {
+func (r *runner) checkNilErr(instr ssa.Instruction, facts *nilErrFacts) {
+	call, ok := instr.(*ssa.Call)
+	if !ok {
+		return
+	}
+
 	funcs := []wrapFunc{
 		{obj: r.libFuncs["github.com/newmo-oss/ergo.WithCode"], arg: 0},
 		{obj: r.libFuncs["github.com/newmo-oss/ergo.Wrap"], arg: 0},
@@ -241,23 +390,50 @@ func (r *runner) checkNilErr(instr ssa.Instruction) {
 			continue
 		}
 
-		if !analysisutil.Called(instr, nil, f.obj) {
+		if !r.called(instr, f.obj) {
 			continue
 		}
 
-		call, ok := instr.(*ssa.Call)
-		if !ok {
-			continue
-		}
+		r.checkNilArg(call, f, facts)
+	}
 
-		if f.arg > len(call.Call.Args)-1 {
-			continue
+	// the function which passes its parameters to ergo.Wrap or ergo.WithCode
+	if fn := staticFunc(call); fn != nil {
+		if fact := facts.wrapParams(fn); fact != nil {
+			for _, param := range fact.Params {
+				r.checkNilArg(call, wrapFunc{obj: fn, arg: param}, facts)
+			}
 		}
+	}
+}
 
-		errarg := call.Call.Args[f.arg]
-		if isNil(call.Block(), errarg) {
-			r.pass.Reportf(instr.Pos(), `The %s argument of %s must not be nil`, ordinalNumber(f.arg+1), f.obj.FullName())
-		}
+func (r *runner) checkNilArg(call *ssa.Call, f wrapFunc, facts *nilErrFacts) {
+	if f.arg > len(call.Call.Args)-1 {
+		return
+	}
+
+	// the receiver is not counted
+	argNum := f.arg + 1
+	if sig, ok := f.obj.Type().(*types.Signature); ok && sig.Recv() != nil {
+		argNum--
+	}
+
+	errarg := call.Call.Args[f.arg]
+	if isNil(call.Block(), errarg) {
+		r.reportf(call.Pos(), `The %s argument of %s must not be nil`, ordinalNumber(argNum), f.obj.FullName())
+		return
+	}
+
+	n, from := facts.nilness(errarg)
+	if n == notNil || from == nil || hasNilGuard(call.Block(), errarg) {
+		return
+	}
+
+	switch n {
+	case alwaysNil:
+		r.reportf(call.Pos(), `The %s argument of %s must not be nil, %s always returns nil error`, ordinalNumber(argNum), f.obj.FullName(), from.FullName())
+	case possiblyNil:
+		r.reportf(call.Pos(), `The %s argument of %s may be nil because %s may return nil error, it should be guarded by a nil check`, ordinalNumber(argNum), f.obj.FullName(), from.FullName())
 	}
 }
 
@@ -301,7 +477,7 @@ func isNil(b *ssa.BasicBlock, v ssa.Value) bool {
 //			// --> (1)
 //			err := ergo.New("error")
 //		}
-//		// --> (2)*
+//		// --> (2)
 //
 //		if err != nil {
 //			// --> ((3))
@@ -315,11 +491,13 @@ func isNil(b *ssa.BasicBlock, v ssa.Value) bool {
 // The control flow graph of function f can be represented as follows.
 // A node of the graph means a basic block of SSA form.
 // The node numbers correspond to the comments in the function f.
-// There are three types of node, start node, terminate node and star node.
+// There are two types of node, start node and terminate node.
 // A start node is a first block of a function.
 // A terminate node is the block which has a return instruction (*ssa.Return).
-// A star node is the block which has an if instruction (*ssa.If) such as if err != nil.
-// Here we call if instructions such as if err != nil, "nil guards".
+// There are two types of edge, normal edge and star edge.
+// A star edge is the branch of an if instruction (*ssa.If) which is taken only if the value is not nil,
+// such as the then branch of if err != nil or the else branch of if err == nil.
+// Here we call the star edges "nil guards".
 //
 //	(0)
 //	 |
@@ -327,7 +505,7 @@ func isNil(b *ssa.BasicBlock, v ssa.Value) bool {
 //	 +---->(1)
 //	 |      |
 //	 | else | if err != nil then
-//	 +---->(2)*----->((3))
+//	 +---->(2)=====>((3))
 //	        |
 //	        | else
 //	        v
@@ -335,18 +513,29 @@ func isNil(b *ssa.BasicBlock, v ssa.Value) bool {
 //
 //	(0)  : Start node (first block)
 //	((n)): Terminate node (return block)
-//	(n)* : Star node (the block has if err != nil instructions)
+//	====>: Star edge (nil guard)
+//
+// The following conditions are recognized as nil guards (see toNilGuard).
+//
+//   - err != nil, nil != err (then branch)
+//   - err == nil, nil == err (else branch)
+//   - errors.Is(err, target) with non-nil target, errors.As(err, &target) (then branch)
+//
+// Compound conditions such as ok && err != nil and switch statements such as switch { case err != nil: }
+// are also recognized because they are compiled into the if instructions of each condition.
 //
 // The algorithms of hasNilGuard are divided into the following:
 //
-//  1. Finding nil guards (a star node) in referrers of the given ssa.Value.
-//  2. Removing the star nodes from the control flow graph.
-//     2.1 In fact, the star node will be ignored in backtracing.
+//  1. Finding nil guards (star edges) in referrers of the given ssa.Value.
+//  2. Removing the star edges from the control flow graph.
+//     2.1 In fact, the star edges will be ignored in backtracing.
 //  3. Backtracing the control flow graph from the given ssa.BasicBlock (node) to the start node.
 //
-// If it reached to the start node, the control flow graph has one or more pathes which are not including star nodes (nil guards), between from the given ssa.Block to the start node.
+// If it reached to the start node, the control flow graph has one or more pathes which are not including star edges (nil guards), between from the given ssa.Block to the start node.
 func hasNilGuard(b *ssa.BasicBlock, v ssa.Value) bool {
-	if _, ok := v.(ssa.Instruction); !ok {
+	switch v.(type) {
+	case ssa.Instruction, *ssa.Parameter:
+	default:
 		return false
 	}
 
@@ -355,9 +544,9 @@ func hasNilGuard(b *ssa.BasicBlock, v ssa.Value) bool {
 		return false
 	}
 
-	guards := make(map[ssa.Instruction]bool)
+	guards := make(map[nilGuard]bool)
 	for _, ref := range *refs {
-		if guard := toNilGuard(v, ref); guard != nil {
+		if guard, ok := toNilGuard(v, ref); ok {
 			guards[guard] = true
 		}
 	}
@@ -366,12 +555,8 @@ func hasNilGuard(b *ssa.BasicBlock, v ssa.Value) bool {
 		return false
 	}
 
-	if backtrace(b, make(map[*ssa.BasicBlock]bool), func(b *ssa.BasicBlock) bool {
-		n := len(b.Instrs)
-		if n == 0 {
-			return false
-		}
-		return guards[b.Instrs[n-1]]
+	if backtrace(b, make(map[*ssa.BasicBlock]bool), func(pre, b *ssa.BasicBlock) bool {
+		return guards[nilGuard{from: pre, to: b}]
 	}, func(b *ssa.BasicBlock) bool {
 		return b.Index == 0
 	}) {
@@ -381,29 +566,80 @@ func hasNilGuard(b *ssa.BasicBlock, v ssa.Value) bool {
 	return true
 }
 
-func toNilGuard(v ssa.Value, instr ssa.Instruction) *ssa.If {
-	cond, ok := instr.(*ssa.BinOp)
-	if !ok {
-		return nil
-	}
+// nilGuard is a branch of an if instruction which is taken only if a value is not nil.
+type nilGuard struct {
+	// from is the block which has the if instruction.
+	from *ssa.BasicBlock
+	// to is the successor of the branch.
+	to *ssa.BasicBlock
+}
 
-	guard := getIf(cond)
-	if guard == nil {
-		return nil
-	}
+func toNilGuard(v ssa.Value, instr ssa.Instruction) (nilGuard, bool) {
+	switch cond := instr.(type) {
+	case *ssa.BinOp:
+		var branch int
+		switch cond.Op {
+		case token.NEQ:
+			// then
+			branch = 0
+		case token.EQL:
+			// else
+			branch = 1
+		default:
+			return nilGuard{}, false
+		}
 
-	if equalValue(cond.X, v) && isNil(instr.Block(), cond.Y) {
-		return guard
-	}
+		guard := getIf(cond)
+		if guard == nil {
+			return nilGuard{}, false
+		}
+
+		if (equalValue(cond.X, v) && isNil(instr.Block(), cond.Y)) ||
+			(equalValue(cond.Y, v) && isNil(instr.Block(), cond.X)) {
+			return newNilGuard(guard, branch)
+		}
+	case *ssa.Call:
+		callee := cond.Call.StaticCallee()
+		if callee == nil || callee.Pkg == nil || callee.Pkg.Pkg.Path() != "errors" || len(cond.Call.Args) != 2 {
+			return nilGuard{}, false
+		}
+
+		switch callee.Name() {
+		case "Is":
+			// errors.Is(nil, nil) returns true
+			if isNilConst(cond.Call.Args[1]) {
+				return nilGuard{}, false
+			}
+		case "As":
+		default:
+			return nilGuard{}, false
+		}
+
+		if !equalValue(cond.Call.Args[0], v) {
+			return nilGuard{}, false
+		}
+
+		guard := getIf(cond)
+		if guard == nil {
+			return nilGuard{}, false
+		}
 
-	if equalValue(cond.Y, v) && isNil(instr.Block(), cond.X) {
-		return guard
+		// then
+		return newNilGuard(guard, 0)
 	}
 
-	return nil
+	return nilGuard{}, false
 }
 
-func getIf(cond *ssa.BinOp) *ssa.If {
+func newNilGuard(guard *ssa.If, branch int) (nilGuard, bool) {
+	b := guard.Block()
+	if len(b.Succs) != 2 || b.Succs[0] == b.Succs[1] {
+		return nilGuard{}, false
+	}
+	return nilGuard{from: b, to: b.Succs[branch]}, true
+}
+
+func getIf(cond ssa.Value) *ssa.If {
 	refs := cond.Referrers()
 	if refs == nil {
 		return nil
@@ -457,7 +693,7 @@ func phiValues(v ssa.Value) iter.Seq[ssa.Value] {
 	}
 }
 
-func backtrace(b *ssa.BasicBlock, done map[*ssa.BasicBlock]bool, drop, terminate func(b *ssa.BasicBlock) bool) bool {
+func backtrace(b *ssa.BasicBlock, done map[*ssa.BasicBlock]bool, drop func(pre, b *ssa.BasicBlock) bool, terminate func(b *ssa.BasicBlock) bool) bool {
 	if done[b] {
 		return false
 	}
@@ -468,7 +704,7 @@ func backtrace(b *ssa.BasicBlock, done map[*ssa.BasicBlock]bool, drop, terminate
 	}
 
 	for _, pre := range b.Preds {
-		if !drop(pre) {
+		if !drop(pre, b) {
 			if backtrace(pre, done, drop, terminate) {
 				return true
 			}
@@ -515,9 +751,9 @@ func (r *runner) checkVarInit() {
 
 					switch fun {
 					case ergoNew:
-						r.pass.Reportf(call.Pos(), "%s must not be used in package variable initilization, it should be replaced by ergo.NewSentinel", fun.FullName())
+						r.reportf(call.Pos(), "%s must not be used in package variable initilization, it should be replaced by ergo.NewSentinel", fun.FullName())
 					case ergoWrap:
-						r.pass.Reportf(call.Pos(), "%s must not be used in package variable initilization, it should be replaced by errors.Join", fun.FullName())
+						r.reportf(call.Pos(), "%s must not be used in package variable initilization, it should be replaced by errors.Join", fun.FullName())
 					}
 				}
 			}
@@ -525,18 +761,1303 @@ func (r *runner) checkVarInit() {
 	}
 }
 
+// getCallFun returns the function of the callee expression.
+// The function is resolved via explicit instantiations of generic functions,
+// function variables which are assigned only once and generic wrappers by [shared.funcOf].
 func (r *runner) getCallFun(fun ast.Expr) *types.Func {
-	switch fun := fun.(type) {
+	return r.getCallFunWithDone(fun, make(map[types.Object]bool))
+}
+
+func (r *runner) getCallFunWithDone(fun ast.Expr, done map[types.Object]bool) *types.Func {
+	var id *ast.Ident
+	switch fun := ast.Unparen(fun).(type) {
+	case *ast.IndexExpr:
+		return r.getCallFunWithDone(fun.X, done)
+	case *ast.IndexListExpr:
+		return r.getCallFunWithDone(fun.X, done)
 	case *ast.Ident:
-		obj, ok := r.pass.TypesInfo.ObjectOf(fun).(*types.Func)
-		if ok {
-			return obj
-		}
+		id = fun
 	case *ast.SelectorExpr:
-		obj, ok := r.pass.TypesInfo.ObjectOf(fun.Sel).(*types.Func)
-		if ok {
-			return obj
+		id = fun.Sel
+	default:
+		return nil
+	}
+
+	obj := r.pass.TypesInfo.ObjectOf(id)
+	if obj == nil || done[obj] {
+		return nil
+	}
+	done[obj] = true
+
+	switch obj := obj.(type) {
+	case *types.Func:
+		if r.ssa != nil && obj.Pkg() == r.pass.Pkg {
+			if fn := r.ssa.Pkg.Prog.FuncValue(obj); fn != nil && fn.TypeParams().Len() > 0 {
+				if f := r.forwardedFunc(fn, make(map[ssa.Value]bool)); f != nil {
+					return f
+				}
+			}
+		}
+		return obj
+	case *types.Var:
+		if init, ok := r.funcVars[obj]; ok {
+			return r.getCallFunWithDone(init, done)
+		}
+	}
+
+	return nil
+}
+
+// checkNewCode checks calls of ergo.NewCode.
+// ergo.NewCode obtains the package path of a code from its caller,
+// thus it must be called directly in a package variable initialization such as:
+//
+//	var CodeNotFound = ergo.NewCode("NotFound", "not found")
+//
+// In addition, the key of a code must be a constant and must be unique in the package.
+func (r *runner) checkNewCode() {
+	ergoNewCode, ok := r.libFuncs["github.com/newmo-oss/ergo.NewCode"]
+	if !ok {
+		return
+	}
+
+	varInits := r.varInitCalls()
+
+	keys := make(map[string]token.Pos)
+	for _, file := range r.pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || r.getCallFun(call.Fun) != ergoNewCode {
+				return true
+			}
+
+			if _, ok := varInits[call]; !ok {
+				r.reportf(call.Pos(), "%s must be called only in package variable initialization", ergoNewCode.FullName())
+				return true
+			}
+
+			if len(call.Args) == 0 {
+				return true
+			}
+
+			key := r.pass.TypesInfo.Types[call.Args[0]].Value
+			if key == nil || key.Kind() != constant.String {
+				r.reportf(call.Args[0].Pos(), "the key of %s must be a constant string", ergoNewCode.FullName())
+				return true
+			}
+
+			keyStr := constant.StringVal(key)
+			if pos, ok := keys[keyStr]; ok {
+				// the position is not in the message to keep fingerprints of baselines stable
+				r.report(analysis.Diagnostic{
+					Pos:     call.Args[0].Pos(),
+					Message: fmt.Sprintf("the key %q of %s is duplicated with another code", keyStr, ergoNewCode.FullName()),
+					Related: []analysis.RelatedInformation{{Pos: pos, Message: "the other code is declared here"}},
+				})
+				return true
+			}
+			keys[keyStr] = call.Args[0].Pos()
+
+			return true
+		})
+	}
+}
+
+// varInitCall is a call which is a direct initializer of a package variable.
+type varInitCall struct {
+	// name is the name of the package variable.
+	// It is nil if the call returns multiple values.
+	name *ast.Ident
+	file *ast.File
+}
+
+// varInitCalls returns the calls which are direct initializers of package variables such as:
+//
+//	var ErrNotFound = ergo.NewSentinel("not found")
+func (r *runner) varInitCalls() map[*ast.CallExpr]varInitCall {
+	varInits := make(map[*ast.CallExpr]varInitCall)
+	for _, file := range r.pass.Files {
+		for _, decl := range file.Decls {
+			gendecl, ok := decl.(*ast.GenDecl)
+			if !ok || gendecl.Tok != token.VAR {
+				continue
+			}
+
+			for _, spec := range gendecl.Specs {
+				valspec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+
+				for i, val := range valspec.Values {
+					call, ok := ast.Unparen(val).(*ast.CallExpr)
+					if !ok {
+						continue
+					}
+
+					init := varInitCall{file: file}
+					if len(valspec.Names) == len(valspec.Values) {
+						init.name = valspec.Names[i]
+					}
+					varInits[call] = init
+				}
+			}
+		}
+	}
+	return varInits
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+// checkErrComparison checks comparisons of errors.
+// An error freshly created by ergo.New or ergo.Wrap which is used only by comparisons
+// is not wrapped by any other error, thus it never matches with errors.Is and ==.
+// A comparison with a sentinel error by == or != cannot find the wrapped sentinel error,
+// thus it should be replaced by errors.Is.
+func (r *runner) checkErrComparison(instr ssa.Instruction) {
+	switch instr := instr.(type) {
+	case *ssa.BinOp:
+		if instr.Op != token.EQL && instr.Op != token.NEQ {
+			return
+		}
+
+		if !types.Identical(instr.X.Type(), errorType) || !types.Identical(instr.Y.Type(), errorType) {
+			return
+		}
+
+		// err != nil
+		if isNilConst(instr.X) || isNilConst(instr.Y) {
+			return
+		}
+
+		for _, v := range []ssa.Value{instr.X, instr.Y} {
+			if f := r.freshlyCreatedBy(v); f != nil {
+				r.reportf(instr.Pos(), "the error freshly created by %s is not wrapped by any error, thus the comparison never matches, compare with a sentinel error created by ergo.NewSentinel", f.FullName())
+				return
+			}
+		}
+
+		for _, v := range []ssa.Value{instr.X, instr.Y} {
+			if g := sentinelOf(v); g != nil {
+				r.reportf(instr.Pos(), "the comparison with the sentinel error %s by %s should be replaced by errors.Is", g.Name(), instr.Op)
+				return
+			}
+		}
+	case *ssa.Call:
+		errorsIs, ok := r.libFuncs["errors.Is"]
+		if !ok || !r.called(instr, errorsIs) {
+			return
+		}
+
+		if len(instr.Call.Args) < 2 {
+			return
+		}
+
+		if f := r.freshlyCreatedBy(instr.Call.Args[1]); f != nil {
+			r.reportf(instr.Pos(), "the 2nd argument of %s is freshly created by %s and is not wrapped by any error, thus it never matches, use a sentinel error created by ergo.NewSentinel", errorsIs.FullName(), f.FullName())
+		}
+	}
+}
+
+// freshlyCreatedBy returns the function (ergo.New or ergo.Wrap) which creates the given value
+// if the value is used only by comparisons.
+// The value is traced via phi nodes and all the values merged by them must be such values,
+// because an error which is wrapped, returned or stored may match with the comparison.
+// Package variables are not traced because they are checked by varinit.
+func (r *runner) freshlyCreatedBy(v ssa.Value) *types.Func {
+	var created *types.Func
+	for v := range phiValues(v) {
+		call, ok := v.(*ssa.Call)
+		if !ok {
+			return nil
+		}
+
+		f := r.createdBy(call)
+		if f == nil || !r.onlyCompared(call, make(map[ssa.Value]bool)) {
+			return nil
+		}
+
+		if created == nil {
+			created = f
+		}
+	}
+	return created
+}
+
+// createdBy returns the function (ergo.New or ergo.Wrap) which is called by the call.
+func (r *runner) createdBy(call *ssa.Call) *types.Func {
+	for _, f := range []*types.Func{
+		r.libFuncs["github.com/newmo-oss/ergo.New"],
+		r.libFuncs["github.com/newmo-oss/ergo.Wrap"],
+	} {
+		if f != nil && r.called(call, f) {
+			return f
 		}
 	}
 	return nil
 }
+
+// onlyCompared reports whether the value is used only as the 2nd argument of errors.Is,
+// an operand of == or != with another value and via phi nodes.
+func (r *runner) onlyCompared(v ssa.Value, done map[ssa.Value]bool) bool {
+	if done[v] {
+		return true
+	}
+	done[v] = true
+
+	refs := v.Referrers()
+	if refs == nil {
+		return true
+	}
+
+	errorsIs := r.libFuncs["errors.Is"]
+	for _, ref := range *refs {
+		switch ref := ref.(type) {
+		case *ssa.DebugRef:
+			// not a use
+		case *ssa.Phi:
+			if !r.onlyCompared(ref, done) {
+				return false
+			}
+		case *ssa.BinOp:
+			if (ref.Op != token.EQL && ref.Op != token.NEQ) || ref.X == ref.Y {
+				return false
+			}
+		case *ssa.Call:
+			args := ref.Call.Args
+			if errorsIs == nil || !r.called(ref, errorsIs) || len(args) < 2 || args[0] == v || args[1] != v {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func isNilConst(v ssa.Value) bool {
+	c, ok := v.(*ssa.Const)
+	return ok && c.IsNil()
+}
+
+// sentinelOf returns the package variable of error type if the value is loaded from it.
+func sentinelOf(v ssa.Value) *ssa.Global {
+	load, ok := v.(*ssa.UnOp)
+	if !ok || load.Op != token.MUL {
+		return nil
+	}
+
+	global, ok := load.X.(*ssa.Global)
+	if !ok || !types.Identical(load.Type(), errorType) {
+		return nil
+	}
+
+	return global
+}
+
+// checkAttrErr checks errors which are passed as attribute values of ergo.New and ergo.Wrap such as:
+//
+//	ergo.New("failed", slog.Any("err", err))
+//	ergo.New("failed", slog.String("err", err.Error()))
+//
+// These errors lose the error chain, thus errors.Is, ergo.CodeOf and ergo.StackTraceOf cannot find them.
+// When ergo.New has only one such attribute, it suggests replacing it with ergo.Wrap.
+func (r *runner) checkAttrErr() {
+	ergoNew, ok := r.libFuncs["github.com/newmo-oss/ergo.New"]
+	if !ok {
+		return
+	}
+
+	ergoWrap, ok := r.libFuncs["github.com/newmo-oss/ergo.Wrap"]
+	if !ok {
+		return
+	}
+
+	for _, file := range r.pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || call.Ellipsis.IsValid() {
+				return true
+			}
+
+			var msgarg int
+			fun := r.getCallFun(call.Fun)
+			switch fun {
+			case ergoNew:
+				msgarg = 0
+			case ergoWrap:
+				msgarg = 1
+			default:
+				return true
+			}
+
+			if len(call.Args) <= msgarg+1 {
+				return true
+			}
+
+			var (
+				errAttrs []int
+				errExprs []ast.Expr
+			)
+			for i, arg := range call.Args[msgarg+1:] {
+				errExpr := r.attrErr(arg)
+				if errExpr == nil {
+					continue
+				}
+				errAttrs = append(errAttrs, msgarg+1+i)
+				errExprs = append(errExprs, errExpr)
+			}
+
+			for i, idx := range errAttrs {
+				diag := analysis.Diagnostic{
+					Pos:     call.Args[idx].Pos(),
+					End:     call.Args[idx].End(),
+					Message: fmt.Sprintf("the error passed as an attribute value of %s loses the error chain, it should be wrapped by ergo.Wrap", fun.FullName()),
+				}
+
+				if fun == ergoNew && len(errAttrs) == 1 {
+					if fix, ok := r.wrapFix(file, call, errExprs[i], idx); ok {
+						diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+					}
+				}
+
+				r.report(diag)
+			}
+
+			return true
+		})
+	}
+}
+
+// attrErr returns the error expression if the given expression is an attribute which has an error as its value.
+func (r *runner) attrErr(expr ast.Expr) ast.Expr {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 {
+		return nil
+	}
+
+	errorIface, _ := errorType.Underlying().(*types.Interface)
+	val := ast.Unparen(call.Args[1])
+	switch r.getCallFun(call.Fun) {
+	case nil:
+		return nil
+	case r.libFuncs["log/slog.Any"]:
+		typ := r.pass.TypesInfo.TypeOf(val)
+		if typ == nil || types.Identical(typ, types.Typ[types.UntypedNil]) || !types.Implements(typ, errorIface) {
+			return nil
+		}
+		return val
+	case r.libFuncs["log/slog.String"]:
+		// err.Error()
+		errCall, ok := val.(*ast.CallExpr)
+		if !ok || len(errCall.Args) != 0 {
+			return nil
+		}
+
+		sel, ok := errCall.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Error" {
+			return nil
+		}
+
+		typ := r.pass.TypesInfo.TypeOf(sel.X)
+		if typ == nil || !types.Implements(typ, errorIface) {
+			return nil
+		}
+		return sel.X
+	}
+
+	return nil
+}
+
+// wrapFix creates a suggested fix which replaces ergo.New(msg, slog.Any("err", err)) with ergo.Wrap(err, msg).
+// The fix is created only if the call refers to ergo.New directly, not via a function variable or a wrapper.
+func (r *runner) wrapFix(file *ast.File, call *ast.CallExpr, errExpr ast.Expr, attrIdx int) (analysis.SuggestedFix, bool) {
+	var id *ast.Ident
+	switch f := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident: // dot import
+		id = f
+	case *ast.SelectorExpr:
+		id = f.Sel
+	}
+
+	if id == nil || r.pass.TypesInfo.Uses[id] != r.libFuncs["github.com/newmo-oss/ergo.New"] {
+		return analysis.SuggestedFix{}, false
+	}
+
+	ergoSpec := importSpec(file, "github.com/newmo-oss/ergo")
+	if ergoSpec == nil {
+		return analysis.SuggestedFix{}, false
+	}
+
+	fun := "ergo.Wrap"
+	switch {
+	case ergoSpec.Name == nil:
+	case ergoSpec.Name.Name == ".":
+		fun = "Wrap"
+	default:
+		fun = ergoSpec.Name.Name + ".Wrap"
+	}
+
+	args := make([]string, 0, len(call.Args))
+	for _, arg := range append([]ast.Expr{errExpr}, call.Args...) {
+		if arg == call.Args[attrIdx] {
+			continue
+		}
+
+		s, ok := r.nodeString(arg)
+		if !ok {
+			return analysis.SuggestedFix{}, false
+		}
+		args = append(args, s)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s(", fun)
+	for i, arg := range args {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(arg)
+	}
+	buf.WriteString(")")
+
+	return analysis.SuggestedFix{
+		Message: "replace with ergo.Wrap",
+		TextEdits: []analysis.TextEdit{{
+			Pos:     call.Pos(),
+			End:     call.End(),
+			NewText: buf.Bytes(),
+		}},
+	}, true
+}
+
+func (r *runner) nodeString(n ast.Node) (string, bool) {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, r.pass.Fset, n); err != nil {
+		return "", false
+	}
+	return buf.String(), true
+}
+
+// checkRedundantWrap checks redundant wrapping as follows:
+//
+//	// empty message and no attributes
+//	ergo.Wrap(err, "")
+//
+//	// wrapping twice in a function
+//	err = ergo.Wrap(err, "a")
+//	return ergo.Wrap(err, "b")
+//
+//	// the error already has a code
+//	err = ergo.WithCode(err, CodeA)
+//	return ergo.WithCode(ergo.Wrap(err, "wrap"), CodeB)
+func (r *runner) checkRedundantWrap(instr ssa.Instruction) {
+	call, ok := instr.(*ssa.Call)
+	if !ok {
+		return
+	}
+
+	ergoWrap := r.libFuncs["github.com/newmo-oss/ergo.Wrap"]
+	ergoWithCode := r.libFuncs["github.com/newmo-oss/ergo.WithCode"]
+
+	switch {
+	case ergoWrap != nil && r.called(instr, ergoWrap):
+		if len(call.Call.Args) < 2 {
+			return
+		}
+
+		if msg, ok := constString(call.Call.Args[1]); ok && msg == "" && (len(call.Call.Args) < 3 || isNilConst(call.Call.Args[2])) {
+			r.reportf(instr.Pos(), "%s with an empty message and no attributes is redundant, the error should be returned as it is", ergoWrap.FullName())
+			return
+		}
+
+		parent, ok := call.Call.Args[0].(*ssa.Call)
+		if ok && r.called(parent, ergoWrap) {
+			r.reportf(instr.Pos(), "the error is wrapped by %s twice in the function, the wraps should be merged into one", ergoWrap.FullName())
+		}
+	case ergoWithCode != nil && r.called(instr, ergoWithCode):
+		if len(call.Call.Args) < 1 {
+			return
+		}
+
+		if coded := r.codedBy(call.Call.Args[0], make(map[ssa.Value]bool)); coded != nil {
+			r.report(analysis.Diagnostic{
+				Pos:     instr.Pos(),
+				Message: fmt.Sprintf("the error already has a code attached by %s, the code is hidden by the new code", ergoWithCode.FullName()),
+				Related: []analysis.RelatedInformation{{Pos: coded.Pos(), Message: "the code is attached here"}},
+			})
+		}
+	}
+}
+
+// codedBy returns the call of ergo.WithCode if the error statically has a code.
+// The error is traced via the parents of ergo.Wrap and all edges of phi nodes.
+func (r *runner) codedBy(v ssa.Value, done map[ssa.Value]bool) *ssa.Call {
+	if done[v] {
+		return nil
+	}
+	done[v] = true
+
+	ergoWrap := r.libFuncs["github.com/newmo-oss/ergo.Wrap"]
+	ergoWithCode := r.libFuncs["github.com/newmo-oss/ergo.WithCode"]
+
+	switch v := v.(type) {
+	case *ssa.Call:
+		switch {
+		case ergoWithCode != nil && r.called(v, ergoWithCode):
+			return v
+		case ergoWrap != nil && r.called(v, ergoWrap):
+			if len(v.Call.Args) > 0 {
+				return r.codedBy(v.Call.Args[0], done)
+			}
+		}
+	case *ssa.Phi:
+		var coded *ssa.Call
+		for _, edge := range v.Edges {
+			c := r.codedBy(edge, done)
+			if c == nil {
+				return nil
+			}
+
+			if coded == nil {
+				coded = c
+			}
+		}
+		return coded
+	}
+
+	return nil
+}
+
+// constString returns the value of the constant string.
+func constString(v ssa.Value) (string, bool) {
+	c, ok := v.(*ssa.Const)
+	if !ok || c.Value == nil || c.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(c.Value), true
+}
+
+// checkMessageStyle checks the constant messages of ergo.New and ergo.Wrap:
+//
+//   - a message must start with a lowercase letter except acronyms such as "HTTP"
+//   - a message must not end with a period or a newline
+//   - a message must not start with the forbidden prefixes such as "error:"
+//   - a message must not be longer than the maximum length
+//
+// It also checks stutters such as "failed to read: failed to read",
+// which are caused by a message of ergo.Wrap which repeats the statically known message of the wrapped error.
+// The messages stutter if one of them is the leading words of the other,
+// thus "user not found" does not stutter with "not found".
+func (r *runner) checkMessageStyle(instr ssa.Instruction) {
+	call, ok := instr.(*ssa.Call)
+	if !ok {
+		return
+	}
+
+	funcs := []formatFunc{
+		{obj: r.libFuncs["github.com/newmo-oss/ergo.New"], arg: 0},
+		{obj: r.libFuncs["github.com/newmo-oss/ergo.Wrap"], arg: 1},
+	}
+
+	for _, f := range funcs {
+		if f.obj == nil || !r.called(instr, f.obj) || f.arg > len(call.Call.Args)-1 {
+			continue
+		}
+
+		msg, ok := constString(call.Call.Args[f.arg])
+		if !ok || msg == "" {
+			continue
+		}
+
+		if problem := r.messageStyle(msg); problem != "" {
+			r.reportf(instr.Pos(), "the message of %s %s: %q", f.obj.FullName(), problem, msg)
+		}
+
+		if f.arg == 0 {
+			continue
+		}
+
+		parent, ok := r.messageOf(call.Call.Args[0], make(map[ssa.Value]bool))
+		if ok && parent != "" && (hasWordPrefix(msg, parent) || hasWordPrefix(parent, msg)) {
+			r.reportf(instr.Pos(), "the message of %s stutters with the message of the wrapped error: %q: %q", f.obj.FullName(), msg, parent)
+		}
+	}
+}
+
+// messageStyle returns the problem of the message or an empty string.
+func (r *runner) messageStyle(msg string) string {
+	first, _, _ := strings.Cut(msg, " ")
+	if c, _ := utf8.DecodeRuneInString(first); unicode.IsUpper(c) && strings.ToUpper(first) != first {
+		return "must start with a lowercase letter"
+	}
+
+	if strings.HasSuffix(msg, ".") || strings.HasSuffix(msg, "\n") {
+		return "must not end with a period or a newline"
+	}
+
+	for _, prefix := range r.config.Prefixes(r.name) {
+		if prefix != "" && strings.HasPrefix(strings.ToLower(msg), strings.ToLower(prefix)) {
+			return fmt.Sprintf("must not start with %q", prefix)
+		}
+	}
+
+	if maxLength := r.config.MaxLength(r.name); utf8.RuneCountInString(msg) > maxLength {
+		return fmt.Sprintf("must not be longer than %d characters", maxLength)
+	}
+
+	return ""
+}
+
+// hasWordPrefix reports whether s begins with prefix followed by a word boundary.
+func hasWordPrefix(s, prefix string) bool {
+	rest, ok := strings.CutPrefix(s, prefix)
+	if !ok || rest == "" {
+		return ok
+	}
+
+	last, _ := utf8.DecodeLastRuneInString(prefix)
+	next, _ := utf8.DecodeRuneInString(rest)
+	return !isWordRune(last) || !isWordRune(next)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// messageOf returns the statically known message of the error.
+// The error is traced via ergo.WithCode and package variables.
+func (r *runner) messageOf(v ssa.Value, done map[ssa.Value]bool) (string, bool) {
+	if done[v] {
+		return "", false
+	}
+	done[v] = true
+
+	switch v := v.(type) {
+	case *ssa.Call:
+		funcs := []formatFunc{
+			{obj: r.libFuncs["github.com/newmo-oss/ergo.New"], arg: 0},
+			{obj: r.libFuncs["github.com/newmo-oss/ergo.NewSentinel"], arg: 0},
+			{obj: r.libFuncs["errors.New"], arg: 0},
+			{obj: r.libFuncs["github.com/newmo-oss/ergo.Wrap"], arg: 1},
+		}
+
+		for _, f := range funcs {
+			if f.obj != nil && r.called(v, f.obj) && f.arg < len(v.Call.Args) {
+				return constString(v.Call.Args[f.arg])
+			}
+		}
+
+		withCode := r.libFuncs["github.com/newmo-oss/ergo.WithCode"]
+		if withCode != nil && r.called(v, withCode) && len(v.Call.Args) > 0 {
+			return r.messageOf(v.Call.Args[0], done)
+		}
+	case *ssa.UnOp:
+		global := sentinelOf(v)
+		if global == nil {
+			return "", false
+		}
+
+		if init, ok := r.globalInits[global]; ok {
+			return r.messageOf(init, done)
+		}
+	}
+
+	return "", false
+}
+
+// checkPanicErr checks panics with error values such as:
+//
+//	panic(err)
+//	panic(ergo.New("unexpected"))
+//
+// The errors should be returned to the caller instead.
+// The main package and functions whose names start with "Must" are allowed to panic.
+func (r *runner) checkPanicErr(instr ssa.Instruction) {
+	p, ok := instr.(*ssa.Panic)
+	if !ok || r.pass.Pkg.Name() == "main" {
+		return
+	}
+
+	if fn := p.Parent(); fn != nil && strings.HasPrefix(fn.Name(), "Must") {
+		return
+	}
+
+	v := p.X
+	switch x := v.(type) {
+	case *ssa.MakeInterface:
+		v = x.X
+	case *ssa.ChangeInterface:
+		v = x.X
+	}
+
+	if !isError(v.Type()) {
+		return
+	}
+
+	r.reportf(p.Pos(), "panic with an error must not be used in the %s package, the error should be returned", r.pass.Pkg.Path())
+}
+
+// isError reports whether the type implements error.
+func isError(typ types.Type) bool {
+	return types.Implements(typ, errorType.Underlying().(*types.Interface))
+}
+
+// logFuncs are the names of functions and methods of log/slog and log which log their arguments.
+var logFuncs = map[string][]string{
+	"log/slog": {"Debug", "Info", "Warn", "Error", "Log", "LogAttrs", "DebugContext", "InfoContext", "WarnContext", "ErrorContext"},
+	"log":      {"Print", "Printf", "Println", "Fatal", "Fatalf", "Fatalln", "Panic", "Panicf", "Panicln"},
+}
+
+// checkLogReturn checks errors which are both logged and returned in a function such as:
+//
+//	if err != nil {
+//		slog.Error("failed to read", "err", err)
+//		return err
+//	}
+//
+// The caller may log the returned error again, thus the error should be either logged or returned.
+// The log call is reported only if it can reach the return of the error,
+// thus logging the error and retrying in a loop is not reported.
+func (r *runner) checkLogReturn(fn *ssa.Function) {
+	var returned []ssa.Value
+	returnBlocks := make(map[ssa.Value][]*ssa.BasicBlock)
+	for _, b := range fn.Blocks {
+		ret, ok := b.Instrs[len(b.Instrs)-1].(*ssa.Return)
+		if !ok {
+			continue
+		}
+
+		for _, result := range ret.Results {
+			for v := range phiValues(result) {
+				if !isError(v.Type()) || isNilConst(v) {
+					continue
+				}
+
+				if !slices.Contains(returned, v) {
+					returned = append(returned, v)
+				}
+				returnBlocks[v] = append(returnBlocks[v], b)
+			}
+		}
+	}
+
+	for _, v := range returned {
+		for _, call := range r.loggedBy(v) {
+			if !reachesReturn(call, v, returnBlocks[v]) {
+				continue
+			}
+			r.reportf(call.Pos(), "the error is logged by %s and also returned, it should be either logged or returned to avoid duplicated logs", r.calleeOf(call).FullName())
+		}
+	}
+}
+
+// reachesReturn reports whether the log call can reach one of the blocks which return the value
+// without passing the block which defines the value again such as in the next iteration of a loop.
+func reachesReturn(call *ssa.Call, v ssa.Value, returnBlocks []*ssa.BasicBlock) bool {
+	// the return is the last instruction of the block
+	if slices.Contains(returnBlocks, call.Block()) {
+		return true
+	}
+
+	var def *ssa.BasicBlock
+	if instr, ok := v.(ssa.Instruction); ok {
+		def = instr.Block()
+	}
+
+	done := make(map[*ssa.BasicBlock]bool)
+	queue := slices.Clone(call.Block().Succs)
+	for len(queue) > 0 {
+		b := queue[0]
+		queue = queue[1:]
+		if done[b] || b == def {
+			continue
+		}
+		done[b] = true
+
+		if slices.Contains(returnBlocks, b) {
+			return true
+		}
+		queue = append(queue, b.Succs...)
+	}
+
+	return false
+}
+
+// loggedBy returns the calls of log/slog or log which log the error.
+// The error is traced via conversions to interfaces, attributes such as slog.Any,
+// the Error method and variadic arguments.
+func (r *runner) loggedBy(err ssa.Value) []*ssa.Call {
+	var (
+		calls []*ssa.Call
+		done  = make(map[ssa.Value]bool)
+		queue = []ssa.Value{err}
+	)
+
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		if done[v] {
+			continue
+		}
+		done[v] = true
+
+		refs := v.Referrers()
+		if refs == nil {
+			continue
+		}
+
+		for _, ref := range *refs {
+			switch ref := ref.(type) {
+			case *ssa.MakeInterface:
+				queue = append(queue, ref)
+			case *ssa.ChangeInterface:
+				queue = append(queue, ref)
+			case *ssa.Store:
+				// a variadic argument is stored into an array which is sliced
+				if addr, ok := ref.Addr.(*ssa.IndexAddr); ok && ref.Val == v {
+					queue = append(queue, addr.X)
+				}
+			case *ssa.Slice:
+				queue = append(queue, ref)
+			case *ssa.Call:
+				switch fn := r.calleeOf(ref); {
+				case r.isLogCall(ref):
+					if !slices.Contains(calls, ref) {
+						calls = append(calls, ref)
+					}
+				case ref.Call.IsInvoke() && ref.Call.Method.Name() == "Error" && ref.Call.Value == v:
+					// err.Error()
+					queue = append(queue, ref)
+				case fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == "log/slog":
+					// attributes such as slog.Any("err", err)
+					queue = append(queue, ref)
+				}
+			}
+		}
+	}
+
+	return calls
+}
+
+// isLogCall reports whether the call is a call of a logging function or method of log/slog or log.
+func (r *runner) isLogCall(call *ssa.Call) bool {
+	fn := r.calleeOf(call)
+	if fn == nil || fn.Pkg() == nil {
+		return false
+	}
+	return slices.Contains(logFuncs[fn.Pkg().Path()], fn.Name())
+}
+
+// checkSentinel checks sentinel errors which are package variables as follows:
+//
+//	// ergo.NewSentinel outside of package variable initializations
+//	func f() error { return ergo.NewSentinel("not found") }
+//
+//	// the name does not have the prefix Err or err
+//	var NotFound = ergo.NewSentinel("not found")
+//
+//	// reassigning an exported sentinel error
+//	ErrNotFound = ergo.NewSentinel("missing")
+//
+//	// errors.New instead of ergo.NewSentinel
+//	var ErrNotFound = errors.New("not found")
+//
+// A sentinel error which is created at runtime cannot be compared by errors.Is,
+// because each call creates a different error.
+func (r *runner) checkSentinel() {
+	ergoNewSentinel := r.libFuncs["github.com/newmo-oss/ergo.NewSentinel"]
+	errorsNew := r.libFuncs["errors.New"]
+	if ergoNewSentinel == nil && errorsNew == nil {
+		return
+	}
+
+	varInits := r.varInitCalls()
+	sentinels := make(map[types.Object]bool)
+	for _, file := range r.pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			fun := r.getCallFun(call.Fun)
+			if fun == nil || (fun != ergoNewSentinel && fun != errorsNew) {
+				return true
+			}
+
+			init, ok := varInits[call]
+			switch {
+			case !ok && fun == ergoNewSentinel:
+				r.reportf(call.Pos(), "%s must be called only in package variable initialization, a sentinel error created at runtime cannot be compared by errors.Is", fun.FullName())
+				return true
+			case !ok || init.name == nil || init.name.Name == "_":
+				return true
+			}
+
+			obj := r.pass.TypesInfo.ObjectOf(init.name)
+			if obj == nil || !types.Identical(obj.Type(), errorType) {
+				return true
+			}
+			sentinels[obj] = true
+
+			if !strings.HasPrefix(init.name.Name, "Err") && !strings.HasPrefix(init.name.Name, "err") {
+				r.reportf(init.name.Pos(), "the sentinel error %s should be named with the prefix Err such as Err%s", init.name.Name, strings.ToUpper(init.name.Name[:1])+init.name.Name[1:])
+			}
+
+			if fun == errorsNew {
+				diag := analysis.Diagnostic{
+					Pos:     call.Pos(),
+					Message: fmt.Sprintf("%s must not be used for the sentinel error %s, it should be replaced by ergo.NewSentinel", fun.FullName(), init.name.Name),
+				}
+				if fix, ok := r.newSentinelFix(init.file, call); ok {
+					diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+				}
+				r.report(diag)
+			}
+
+			return true
+		})
+	}
+
+	for _, file := range r.pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok {
+				return true
+			}
+
+			for _, lhs := range assign.Lhs {
+				var id *ast.Ident
+				switch lhs := ast.Unparen(lhs).(type) {
+				case *ast.Ident:
+					id = lhs
+				case *ast.SelectorExpr:
+					id = lhs.Sel
+				}
+
+				if id == nil || !id.IsExported() {
+					continue
+				}
+
+				if obj := r.pass.TypesInfo.Uses[id]; obj != nil && sentinels[obj] {
+					r.reportf(lhs.Pos(), "the exported sentinel error %s must not be reassigned", id.Name)
+				}
+			}
+
+			return true
+		})
+	}
+}
+
+// newSentinelFix creates a suggested fix which replaces errors.New with ergo.NewSentinel.
+// It also imports ergo and removes the import of errors if the call is the last use of errors in the file,
+// thus the file can be compiled even if only the fix is applied.
+func (r *runner) newSentinelFix(file *ast.File, call *ast.CallExpr) (analysis.SuggestedFix, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return analysis.SuggestedFix{}, false
+	}
+
+	errorsSpec := importSpec(file, "errors")
+	ergoSpec := importSpec(file, "github.com/newmo-oss/ergo")
+	if errorsSpec == nil {
+		return analysis.SuggestedFix{}, false
+	}
+
+	ergoName := "ergo"
+	if ergoSpec != nil && ergoSpec.Name != nil {
+		ergoName = ergoSpec.Name.Name
+	}
+
+	edits := []analysis.TextEdit{{
+		Pos:     sel.Pos(),
+		End:     sel.End(),
+		NewText: []byte(ergoName + ".NewSentinel"),
+	}}
+
+	unused := true
+	errorsPkg := r.pass.TypesInfo.PkgNameOf(errorsSpec)
+	for id, obj := range r.pass.TypesInfo.Uses {
+		if obj == errorsPkg && id.Pos() >= file.FileStart && id.End() <= file.FileEnd && id != sel.X {
+			unused = false
+			break
+		}
+	}
+
+	if unused {
+		edits = append(edits, deleteImport(file, errorsSpec))
+	}
+
+	if ergoSpec == nil {
+		edits = append(edits, addImport(file, "github.com/newmo-oss/ergo"))
+	}
+
+	return analysis.SuggestedFix{
+		Message:   "replace with ergo.NewSentinel",
+		TextEdits: edits,
+	}, true
+}
+
+// deleteImport returns an edit which deletes the import spec.
+// The import declaration is also deleted if it is not grouped by parentheses.
+func deleteImport(file *ast.File, spec *ast.ImportSpec) analysis.TextEdit {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if ok && gen.Tok == token.IMPORT && !gen.Lparen.IsValid() && slices.Contains(gen.Specs, ast.Spec(spec)) {
+			return analysis.TextEdit{Pos: gen.Pos(), End: gen.End()}
+		}
+	}
+	return analysis.TextEdit{Pos: spec.Pos(), End: spec.End()}
+}
+
+// addImport returns an edit which adds the import of the non-standard package after the last import.
+// The import is added as a new group if the last import is a standard package.
+func addImport(file *ast.File, path string) analysis.TextEdit {
+	var last *ast.GenDecl
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			last = gen
+		}
+	}
+
+	quoted := strconv.Quote(path)
+	switch {
+	case last == nil:
+		return analysis.TextEdit{Pos: file.Name.End(), End: file.Name.End(), NewText: []byte("\n\nimport " + quoted)}
+	case !last.Lparen.IsValid():
+		return analysis.TextEdit{Pos: last.End(), End: last.End(), NewText: []byte("\n\nimport " + quoted)}
+	}
+
+	spec := last.Specs[len(last.Specs)-1].(*ast.ImportSpec)
+	newText := "\n\t" + quoted
+	if p, err := strconv.Unquote(spec.Path.Value); err == nil && !strings.Contains(strings.Split(p, "/")[0], ".") {
+		// a new group after the standard packages
+		newText = "\n" + newText
+	}
+	return analysis.TextEdit{Pos: spec.End(), End: spec.End(), NewText: []byte(newText)}
+}
+
+// importSpec returns the import spec of the package path in the file.
+func importSpec(file *ast.File, path string) *ast.ImportSpec {
+	for _, spec := range file.Imports {
+		if p, err := strconv.Unquote(spec.Path.Value); err == nil && p == path {
+			return spec
+		}
+	}
+	return nil
+}
+
+// checkCodePkg checks the package of the code which is passed to ergo.WithCode.
+// A package should attach only the codes declared in the package itself or the allowed packages
+// such as a shared errcodes package, which are configured by [CheckConfig.CodePackages].
+func (r *runner) checkCodePkg(instr ssa.Instruction) {
+	ergoWithCode := r.libFuncs["github.com/newmo-oss/ergo.WithCode"]
+	if ergoWithCode == nil || !r.called(instr, ergoWithCode) {
+		return
+	}
+
+	call, ok := instr.(*ssa.Call)
+	if !ok || len(call.Call.Args) < 2 {
+		return
+	}
+
+	load, ok := call.Call.Args[1].(*ssa.UnOp)
+	if !ok || load.Op != token.MUL {
+		return
+	}
+
+	global, ok := load.X.(*ssa.Global)
+	if !ok || global.Pkg == nil {
+		return
+	}
+
+	pkgpath, codePkgpath := r.pass.Pkg.Path(), global.Pkg.Pkg.Path()
+	if r.config.CodePackageAllowed(r.name, pkgpath, codePkgpath) {
+		return
+	}
+
+	r.reportf(call.Pos(), "the code %s declared in the %s package must not be attached in the %s package, codes should be declared in the package itself or the allowed packages", global.Name(), codePkgpath, pkgpath)
+}
+
+// checkErrAssert checks type assertions, type switches and comparisons of messages on errors such as:
+//
+//	if myErr, ok := err.(*MyError); ok {}
+//	switch err.(type) {}
+//	if err.Error() == "not found" {}
+//
+// They do not work as expected once the error is wrapped by ergo.Wrap.
+// Assertions to interface types such as err.(interface{ Timeout() bool }) are not checked,
+// because they check the capabilities of the error itself.
+// The results of errors.Unwrap and the targets in Is and As methods are not checked,
+// because they are the errors which are unwrapped intentionally.
+func (r *runner) checkErrAssert() {
+	unwrapped := r.unwrappedVars()
+
+	for _, file := range r.pass.Files {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil && (fn.Name.Name == "Is" || fn.Name.Name == "As") {
+				continue
+			}
+
+			ast.Inspect(decl, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.TypeAssertExpr:
+					// n.Type is nil in a type switch
+					if n.Type != nil && r.isConcreteType(n.Type) && r.assertedError(n.X, unwrapped) {
+						r.reportf(n.Pos(), "type assertion on an error must not be used because the error may be wrapped, errors.As should be used instead")
+					}
+				case *ast.TypeSwitchStmt:
+					var x ast.Expr
+					switch assign := n.Assign.(type) {
+					case *ast.ExprStmt:
+						x = assign.X
+					case *ast.AssignStmt:
+						if len(assign.Rhs) == 1 {
+							x = assign.Rhs[0]
+						}
+					}
+
+					assert, ok := ast.Unparen(x).(*ast.TypeAssertExpr)
+					if !ok || !r.assertedError(assert.X, unwrapped) {
+						return true
+					}
+
+					hasConcreteType := slices.ContainsFunc(n.Body.List, func(stmt ast.Stmt) bool {
+						clause, ok := stmt.(*ast.CaseClause)
+						return ok && slices.ContainsFunc(clause.List, r.isConcreteType)
+					})
+					if hasConcreteType {
+						r.reportf(n.Pos(), "type switch on an error must not be used because the error may be wrapped, errors.As should be used instead")
+					}
+				case *ast.BinaryExpr:
+					if n.Op != token.EQL && n.Op != token.NEQ {
+						return true
+					}
+
+					if (r.isErrorMessage(n.X) && r.isConstString(n.Y)) || (r.isConstString(n.X) && r.isErrorMessage(n.Y)) {
+						r.reportf(n.Pos(), "comparing the message of an error with a string literal must not be used, sentinel errors or codes should be used instead")
+					}
+				case *ast.SwitchStmt:
+					if n.Tag == nil || !r.isErrorMessage(n.Tag) {
+						return true
+					}
+
+					for _, stmt := range n.Body.List {
+						clause, ok := stmt.(*ast.CaseClause)
+						if !ok {
+							continue
+						}
+
+						for _, expr := range clause.List {
+							if r.isConstString(expr) {
+								r.reportf(expr.Pos(), "comparing the message of an error with a string literal must not be used, sentinel errors or codes should be used instead")
+							}
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+}
+
+// unwrappedVars returns the variables which are assigned only results of errors.Unwrap.
+func (r *runner) unwrappedVars() map[types.Object]bool {
+	unwrapped := make(map[types.Object]bool)
+	errorsUnwrap := r.libFuncs["errors.Unwrap"]
+	if errorsUnwrap == nil {
+		return unwrapped
+	}
+
+	assign := func(lhs, rhs ast.Expr) {
+		id, ok := ast.Unparen(lhs).(*ast.Ident)
+		if !ok {
+			return
+		}
+
+		obj := r.pass.TypesInfo.ObjectOf(id)
+		if obj == nil {
+			return
+		}
+
+		call, ok := ast.Unparen(rhs).(*ast.CallExpr)
+		isUnwrap := ok && r.getCallFun(call.Fun) == errorsUnwrap
+		if prev, ok := unwrapped[obj]; !ok || prev {
+			unwrapped[obj] = isUnwrap
+		}
+	}
+
+	for _, file := range r.pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				if len(n.Lhs) == len(n.Rhs) {
+					for i := range n.Lhs {
+						assign(n.Lhs[i], n.Rhs[i])
+					}
+				}
+			case *ast.ValueSpec:
+				if len(n.Names) == len(n.Values) {
+					for i := range n.Names {
+						assign(n.Names[i], n.Values[i])
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	return unwrapped
+}
+
+// assertedError reports whether the expression is an error which should not be asserted.
+func (r *runner) assertedError(x ast.Expr, unwrapped map[types.Object]bool) bool {
+	x = ast.Unparen(x)
+	if !types.Identical(r.pass.TypesInfo.TypeOf(x), errorType) {
+		return false
+	}
+
+	switch x := x.(type) {
+	case *ast.CallExpr:
+		if fun := r.getCallFun(x.Fun); fun != nil && fun == r.libFuncs["errors.Unwrap"] {
+			return false
+		}
+	case *ast.Ident:
+		if unwrapped[r.pass.TypesInfo.ObjectOf(x)] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isConcreteType reports whether the expression is a type which is not an interface type.
+// The nil in a case clause of a type switch is not a type.
+func (r *runner) isConcreteType(expr ast.Expr) bool {
+	tv, ok := r.pass.TypesInfo.Types[expr]
+	return ok && tv.IsType() && !types.IsInterface(tv.Type)
+}
+
+// isErrorMessage reports whether the expression is a call of the Error method of an error.
+func (r *runner) isErrorMessage(x ast.Expr) bool {
+	call, ok := ast.Unparen(x).(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return false
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Error" {
+		return false
+	}
+
+	typ := r.pass.TypesInfo.TypeOf(sel.X)
+	return typ != nil && isError(typ)
+}
+
+// isConstString reports whether the expression is a constant string.
+func (r *runner) isConstString(x ast.Expr) bool {
+	value := r.pass.TypesInfo.Types[x].Value
+	return value != nil && value.Kind() == constant.String
+}
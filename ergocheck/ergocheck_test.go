@@ -1,21 +1,51 @@
 package ergocheck_test
 
 import (
+	"bytes"
+	"os"
 	"testing"
 
 	"github.com/gostaticanalysis/testutil"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
 
 	"github.com/newmo-oss/ergo/ergocheck"
 )
 
-// TestAnalyzer is a test for Analyzer.
-func TestAnalyzer(t *testing.T) {
+// testModFile returns go.mod of the packages for test.
+// It does not require the ergo module because the packages use the stub of ergo in testdata/src.
+func testModFile(t *testing.T) *bytes.Reader {
+	t.Helper()
+
+	data, err := os.ReadFile("go.mod")
+	if err != nil {
+		t.Fatal("failed to read go.mod:", err)
+	}
+
+	f, err := modfile.Parse("go.mod", data, nil)
+	if err != nil {
+		t.Fatal("failed to parse go.mod:", err)
+	}
+
+	if err := f.DropRequire("github.com/newmo-oss/ergo"); err != nil {
+		t.Fatal("failed to drop the requirement of ergo:", err)
+	}
+
+	out, err := f.Format()
+	if err != nil {
+		t.Fatal("failed to format go.mod:", err)
+	}
+
+	return bytes.NewReader(out)
+}
+
+// TestAnalyzers is a test for Analyzers.
+func TestAnalyzers(t *testing.T) {
 	t.Parallel()
-	modfile := testutil.ModFile(t, ".", nil)
-	testdata := testutil.WithModules(t, analysistest.TestData(), modfile)
+	testdata := testutil.WithModules(t, analysistest.TestData(), testModFile(t))
 
-	if err := ergocheck.Analyzer.Flags.Set("packages", ".+/a$"); err != nil {
+	if err := ergocheck.Analyzer.Flags.Set("packages", ".+/a(/.+)?$"); err != nil {
 		t.Fatal("failed to set packages to ergocheck.Analyzer")
 	}
 
@@ -24,10 +54,63 @@ func TestAnalyzer(t *testing.T) {
 	}
 
 	// these packages for test are in testdata/src
-	pkgs := []string{
-		"github.com/newmo-oss/a",
-		"github.com/newmo-oss/notarget",
-		"github.com/newmo-oss/exclude",
+	cases := []struct {
+		analyzer *analysis.Analyzer
+		pkgs     []string
+	}{
+		{ergocheck.Analyzer, []string{
+			"github.com/newmo-oss/ergocheck/a",
+			"github.com/newmo-oss/ergocheck/a/all",
+		}},
+		{ergocheck.DeprecatedFuncAnalyzer, []string{
+			"github.com/newmo-oss/deprecatedfunc/a",
+			"github.com/newmo-oss/notarget",
+			"github.com/newmo-oss/exclude",
+			"github.com/newmo-oss/config/a",
+			"github.com/newmo-oss/config/a/cmd/tool",
+		}},
+		{ergocheck.FormatStringAnalyzer, []string{
+			"github.com/newmo-oss/formatstring/a",
+			"github.com/newmo-oss/config/a/disabled",
+		}},
+		{ergocheck.NilErrAnalyzer, []string{"github.com/newmo-oss/nilerr/a"}},
+		{ergocheck.VarInitAnalyzer, []string{"github.com/newmo-oss/varinit/a"}},
+		{ergocheck.NewCodeAnalyzer, []string{"github.com/newmo-oss/newcode/a"}},
+		{ergocheck.ErrComparisonAnalyzer, []string{"github.com/newmo-oss/errcomparison/a"}},
+		{ergocheck.RedundantWrapAnalyzer, []string{"github.com/newmo-oss/redundantwrap/a"}},
+		{ergocheck.MessageStyleAnalyzer, []string{
+			"github.com/newmo-oss/messagestyle/a",
+			"github.com/newmo-oss/messagestyle/a/config",
+		}},
+		{ergocheck.PanicErrAnalyzer, []string{
+			"github.com/newmo-oss/panicerr/a",
+			"github.com/newmo-oss/panicerr/a/cmd",
+		}},
+		{ergocheck.LogReturnAnalyzer, []string{"github.com/newmo-oss/logreturn/a"}},
+		{ergocheck.CodePkgAnalyzer, []string{
+			"github.com/newmo-oss/codepkg/a",
+			"github.com/newmo-oss/codepkg/a/config",
+		}},
+		{ergocheck.ErrAssertAnalyzer, []string{"github.com/newmo-oss/errassert/a"}},
+		{ergocheck.WithDirectives(ergocheck.DeprecatedFuncAnalyzer), []string{"github.com/newmo-oss/ergocheck/a"}},
+	}
+
+	for _, tt := range cases {
+		analysistest.Run(t, testdata, tt.analyzer, tt.pkgs...)
+	}
+
+	// these packages have suggested fixes and golden files.
+	// testutil.WithModules is not used because it prepends line directives to files
+	// which do not exist in golden files.
+	fixCases := []struct {
+		analyzer *analysis.Analyzer
+		pkgs     []string
+	}{
+		{ergocheck.AttrErrAnalyzer, []string{"github.com/newmo-oss/attrerr/a"}},
+		{ergocheck.SentinelAnalyzer, []string{"github.com/newmo-oss/sentinel/a"}},
+	}
+
+	for _, tt := range fixCases {
+		analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), tt.analyzer, tt.pkgs...)
 	}
-	analysistest.Run(t, testdata, ergocheck.Analyzer, pkgs...)
 }
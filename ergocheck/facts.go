@@ -0,0 +1,343 @@
+package ergocheck
+
+import (
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// NilResultFact is a fact of a function whose last result is an error which may be nil.
+// It is exported by [NilErrAnalyzer].
+type NilResultFact struct {
+	// Always reports whether the error is always nil.
+	Always bool
+}
+
+// AFact implements [analysis.Fact].
+func (*NilResultFact) AFact() {}
+
+func (fact *NilResultFact) String() string {
+	if fact.Always {
+		return "alwaysNil"
+	}
+	return "possiblyNil"
+}
+
+// WrapParamFact is a fact of a function whose parameters flow into
+// the 1st argument of ergo.Wrap or ergo.WithCode without nil guards.
+// It is exported by [NilErrAnalyzer].
+type WrapParamFact struct {
+	// Params are the indexes of the parameters.
+	// The index of the receiver of a method is 0.
+	Params []int
+}
+
+// AFact implements [analysis.Fact].
+func (*WrapParamFact) AFact() {}
+
+func (fact *WrapParamFact) String() string {
+	params := make([]string, len(fact.Params))
+	for i, param := range fact.Params {
+		params[i] = fmt.Sprint(param)
+	}
+	return "wrapParams(" + strings.Join(params, ",") + ")"
+}
+
+type nilness int
+
+const (
+	// notNil means the value is not nil or unknown.
+	notNil nilness = iota
+	possiblyNil
+	alwaysNil
+)
+
+// nilErrFacts has the facts of the package and the imported packages.
+type nilErrFacts struct {
+	pass           *analysis.Pass
+	shared         *shared
+	nilResultFacts map[*types.Func]*NilResultFact
+	wrapParamFacts map[*types.Func]*WrapParamFact
+}
+
+// newNilErrFacts computes the facts of the functions in the package and exports them.
+// The facts are computed repeatedly until they become stable,
+// because the functions in the same package may call each other.
+func newNilErrFacts(pass *analysis.Pass, s *shared) *nilErrFacts {
+	facts := &nilErrFacts{
+		pass:           pass,
+		shared:         s,
+		nilResultFacts: make(map[*types.Func]*NilResultFact),
+		wrapParamFacts: make(map[*types.Func]*WrapParamFact),
+	}
+
+	// the functions of ergo are handled by the checks
+	if pass.Pkg.Path() == "github.com/newmo-oss/ergo" || s.ssa == nil {
+		return facts
+	}
+
+	for range len(s.funcs) + 1 {
+		changed := false
+		for _, fn := range s.funcs {
+			obj, ok := fn.Object().(*types.Func)
+			if !ok {
+				continue
+			}
+
+			if facts.updateNilResult(obj, fn) {
+				changed = true
+			}
+
+			if facts.updateWrapParams(obj, fn) {
+				changed = true
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	for obj, fact := range facts.nilResultFacts {
+		pass.ExportObjectFact(obj, fact)
+	}
+
+	for obj, fact := range facts.wrapParamFacts {
+		pass.ExportObjectFact(obj, fact)
+	}
+
+	return facts
+}
+
+func (facts *nilErrFacts) updateNilResult(obj *types.Func, fn *ssa.Function) bool {
+	results := fn.Signature.Results()
+	if results.Len() == 0 || !types.Identical(results.At(results.Len()-1).Type(), errorType) {
+		return false
+	}
+
+	var (
+		returns int
+		always  int
+		nilable bool
+	)
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			ret, ok := instr.(*ssa.Return)
+			if !ok || len(ret.Results) != results.Len() {
+				continue
+			}
+			returns++
+
+			switch n, _ := facts.nilness(ret.Results[len(ret.Results)-1]); n {
+			case alwaysNil:
+				always++
+				nilable = true
+			case possiblyNil:
+				nilable = true
+			}
+		}
+	}
+
+	if !nilable {
+		return false
+	}
+
+	fact := &NilResultFact{Always: returns == always}
+	if old, ok := facts.nilResultFacts[obj]; ok && *old == *fact {
+		return false
+	}
+	facts.nilResultFacts[obj] = fact
+
+	return true
+}
+
+func (facts *nilErrFacts) updateWrapParams(obj *types.Func, fn *ssa.Function) bool {
+	var params []int
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			call, ok := instr.(*ssa.Call)
+			if !ok {
+				continue
+			}
+
+			for _, arg := range facts.wrapArgs(call) {
+				if arg > len(call.Call.Args)-1 {
+					continue
+				}
+
+				errarg := call.Call.Args[arg]
+				for v := range phiValues(errarg) {
+					param, ok := v.(*ssa.Parameter)
+					if !ok || param.Parent() != fn {
+						continue
+					}
+
+					if hasNilGuard(call.Block(), errarg) || hasNilGuard(call.Block(), param) {
+						continue
+					}
+
+					if i := slices.Index(fn.Params, param); i >= 0 && !slices.Contains(params, i) {
+						params = append(params, i)
+					}
+				}
+			}
+		}
+	}
+
+	if len(params) == 0 {
+		return false
+	}
+	slices.Sort(params)
+
+	if old, ok := facts.wrapParamFacts[obj]; ok && slices.Equal(old.Params, params) {
+		return false
+	}
+	facts.wrapParamFacts[obj] = &WrapParamFact{Params: params}
+
+	return true
+}
+
+// wrapArgs returns the indexes of the arguments which flow into the 1st argument of ergo.Wrap or ergo.WithCode.
+func (facts *nilErrFacts) wrapArgs(call *ssa.Call) []int {
+	if callee := facts.shared.calleeOf(call); callee != nil &&
+		(callee == facts.shared.libFuncs["github.com/newmo-oss/ergo.Wrap"] ||
+			callee == facts.shared.libFuncs["github.com/newmo-oss/ergo.WithCode"]) {
+		return []int{0}
+	}
+
+	fn := staticFunc(call)
+	if fn == nil {
+		return nil
+	}
+
+	if fact := facts.wrapParams(fn); fact != nil {
+		return fact.Params
+	}
+
+	return nil
+}
+
+func (facts *nilErrFacts) nilResult(fn *types.Func) *NilResultFact {
+	if fact, ok := facts.nilResultFacts[fn]; ok {
+		return fact
+	}
+
+	var fact NilResultFact
+	if facts.pass.ImportObjectFact(fn, &fact) {
+		return &fact
+	}
+
+	return nil
+}
+
+func (facts *nilErrFacts) wrapParams(fn *types.Func) *WrapParamFact {
+	if fact, ok := facts.wrapParamFacts[fn]; ok {
+		return fact
+	}
+
+	var fact WrapParamFact
+	if facts.pass.ImportObjectFact(fn, &fact) {
+		return &fact
+	}
+
+	return nil
+}
+
+// nilness returns the nilness of the error value and the function which returns the nil error.
+func (facts *nilErrFacts) nilness(v ssa.Value) (nilness, *types.Func) {
+	return facts.nilnessWithDone(v, make(map[ssa.Value]bool))
+}
+
+func (facts *nilErrFacts) nilnessWithDone(v ssa.Value, done map[ssa.Value]bool) (nilness, *types.Func) {
+	if done[v] {
+		return notNil, nil
+	}
+	done[v] = true
+
+	switch v := v.(type) {
+	case *ssa.Const:
+		if v.IsNil() {
+			return alwaysNil, nil
+		}
+	case *ssa.Phi:
+		var (
+			always = true
+			result = notNil
+			from   *types.Func
+		)
+		for _, edge := range v.Edges {
+			n, fn := facts.nilnessWithDone(edge, done)
+			if n != alwaysNil {
+				always = false
+			}
+
+			if n != notNil {
+				result = possiblyNil
+				if from == nil {
+					from = fn
+				}
+			}
+		}
+
+		if always && len(v.Edges) > 0 {
+			return alwaysNil, from
+		}
+		return result, from
+	case *ssa.Call:
+		return facts.nilnessOfCall(v, v.Type())
+	case *ssa.Extract:
+		call, ok := v.Tuple.(*ssa.Call)
+		if !ok {
+			return notNil, nil
+		}
+
+		results, ok := call.Type().(*types.Tuple)
+		if !ok || v.Index != results.Len()-1 {
+			return notNil, nil
+		}
+
+		return facts.nilnessOfCall(call, v.Type())
+	}
+
+	return notNil, nil
+}
+
+func (facts *nilErrFacts) nilnessOfCall(call *ssa.Call, typ types.Type) (nilness, *types.Func) {
+	if !types.Identical(typ, errorType) {
+		return notNil, nil
+	}
+
+	fn := staticFunc(call)
+	if fn == nil {
+		return notNil, nil
+	}
+
+	fact := facts.nilResult(fn)
+	switch {
+	case fact == nil:
+		return notNil, nil
+	case fact.Always:
+		return alwaysNil, fn
+	default:
+		return possiblyNil, fn
+	}
+}
+
+// staticFunc returns the statically called function of the call.
+func staticFunc(call *ssa.Call) *types.Func {
+	callee := call.Call.StaticCallee()
+	if callee == nil {
+		return nil
+	}
+
+	if callee.Origin() != nil {
+		callee = callee.Origin()
+	}
+
+	fn, _ := callee.Object().(*types.Func)
+	return fn
+}
@@ -0,0 +1,30 @@
+module github.com/newmo-oss/ergo/ergocheck
+
+go 1.25.0
+
+require (
+	github.com/golangci/plugin-module-register v0.1.2
+	github.com/google/go-cmp v0.7.0
+	github.com/gostaticanalysis/analysisutil v0.7.1
+	github.com/gostaticanalysis/ssainspect v0.3.0
+	github.com/gostaticanalysis/testutil v0.6.1
+	github.com/newmo-oss/ergo v0.2.0
+	golang.org/x/mod v0.35.0
+	golang.org/x/tools v0.44.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/gostaticanalysis/comment v1.5.0 // indirect
+	github.com/hashicorp/go-version v1.7.0 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/newmo-oss/go-caller v0.1.0 // indirect
+	github.com/otiai10/copy v1.14.1 // indirect
+	github.com/otiai10/mint v1.6.3 // indirect
+	github.com/tenntenn/modver v1.0.1 // indirect
+	github.com/tenntenn/text/transform v0.0.0-20200319021203-7eef512accb3 // indirect
+	golang.org/x/sync v0.20.0 // indirect
+	golang.org/x/sys v0.43.0 // indirect
+	golang.org/x/text v0.23.0 // indirect
+	golang.org/x/tools/go/expect v0.1.1-deprecated // indirect
+)
@@ -0,0 +1,127 @@
+// Package golangci provides ergocheck as a module plugin of golangci-lint.
+//
+//	version: v2.x.x
+//	plugins:
+//	  - module: github.com/newmo-oss/ergo/ergocheck
+//	    import: github.com/newmo-oss/ergo/ergocheck/golangci
+//	    version: vX.X.X
+package golangci
+
+import (
+	"log/slog"
+	"slices"
+
+	"github.com/golangci/plugin-module-register/register"
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/newmo-oss/ergo/ergocheck"
+)
+
+func init() {
+	register.Plugin("ergocheck", New)
+}
+
+// Settings is the settings of the plugin in .golangci.yml.
+//
+//	linters:
+//	  settings:
+//	    custom:
+//	      ergocheck:
+//	        type: module
+//	        settings:
+//	          checks:
+//	            - deprecatedfunc
+//	            - nilerr
+//	          config: .ergocheck.yaml
+type Settings struct {
+	// Checks are the names of enabled checks. All the checks are enabled if it is empty.
+	Checks []string `json:"checks"`
+	// Packages is the regular expression of target packages import path.
+	Packages string `json:"packages"`
+	// Excludes is the regular expression of excluded packages import path.
+	Excludes string `json:"excludes"`
+	// Config is the path of the configuration file.
+	Config string `json:"config"`
+}
+
+// Plugin is the golangci-lint plugin of ergocheck.
+type Plugin struct {
+	settings Settings
+}
+
+var _ register.LinterPlugin = (*Plugin)(nil)
+
+// New creates a new plugin with the settings of golangci-lint.
+func New(settings any) (register.LinterPlugin, error) {
+	s, err := register.DecodeSettings[Settings](settings)
+	if err != nil {
+		return nil, ergo.Wrap(err, "failed to decode the settings of ergocheck")
+	}
+	return &Plugin{settings: s}, nil
+}
+
+// BuildAnalyzers implements [register.LinterPlugin].
+// The first analyzer also reports malformed ignore directives by [ergocheck.WithDirectives],
+// because golangci-lint does not report the diagnostics of the common analyzer.
+func (p *Plugin) BuildAnalyzers() ([]*analysis.Analyzer, error) {
+	flags := map[string]string{
+		"packages": p.settings.Packages,
+		"excludes": p.settings.Excludes,
+		"config":   p.settings.Config,
+	}
+	for name, value := range flags {
+		if err := ergocheck.Analyzer.Flags.Set(name, value); err != nil {
+			return nil, ergo.Wrap(err, "failed to set the flag of ergocheck")
+		}
+	}
+
+	analyzers, err := p.analyzers()
+	if err != nil {
+		return nil, err
+	}
+	analyzers[0] = ergocheck.WithDirectives(analyzers[0])
+
+	return analyzers, nil
+}
+
+// GetLoadMode implements [register.LinterPlugin].
+// The analyzers which use facts such as nilerr need the packages of the dependencies to be analyzed,
+// thus the types info mode is used only if no enabled analyzer uses facts.
+func (p *Plugin) GetLoadMode() string {
+	analyzers, err := p.analyzers()
+	if err != nil || slices.ContainsFunc(analyzers, usesFacts) {
+		return register.LoadModeSyntax
+	}
+	return register.LoadModeTypesInfo
+}
+
+// analyzers returns the analyzers of the enabled checks.
+func (p *Plugin) analyzers() ([]*analysis.Analyzer, error) {
+	for _, name := range p.settings.Checks {
+		if !slices.ContainsFunc(ergocheck.Analyzers, func(a *analysis.Analyzer) bool {
+			return a.Name == name && a.Name != ergocheck.Analyzer.Name
+		}) {
+			return nil, ergo.New("unknown check", slog.String("check", name))
+		}
+	}
+
+	analyzers := make([]*analysis.Analyzer, 0, len(ergocheck.Analyzers))
+	for _, a := range ergocheck.Analyzers {
+		if a.Name == ergocheck.Analyzer.Name {
+			// the common analyzer required by the other analyzers
+			continue
+		}
+
+		if len(p.settings.Checks) == 0 || slices.Contains(p.settings.Checks, a.Name) {
+			analyzers = append(analyzers, a)
+		}
+	}
+
+	return analyzers, nil
+}
+
+// usesFacts reports whether the analyzer exports facts.
+func usesFacts(a *analysis.Analyzer) bool {
+	return len(a.FactTypes) > 0
+}
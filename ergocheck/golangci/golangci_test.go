@@ -0,0 +1,62 @@
+package golangci_test
+
+import (
+	"testing"
+
+	"github.com/golangci/plugin-module-register/register"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/newmo-oss/ergo/ergocheck/golangci"
+)
+
+func TestPlugin(t *testing.T) {
+	cases := map[string]struct {
+		settings any
+
+		want         []string
+		wantLoadMode string
+		wantErr      bool
+	}{
+		"all":           {map[string]any{}, []string{"deprecatedfunc", "formatstring", "nilerr", "varinit", "newcode", "errcomparison", "attrerr", "redundantwrap", "messagestyle", "panicerr", "logreturn", "sentinel", "codepkg", "errassert"}, register.LoadModeSyntax, false},
+		"selected":      {map[string]any{"checks": []string{"nilerr", "varinit"}}, []string{"nilerr", "varinit"}, register.LoadModeSyntax, false},
+		"no facts":      {map[string]any{"checks": []string{"varinit", "errassert"}}, []string{"varinit", "errassert"}, register.LoadModeTypesInfo, false},
+		"unknown check": {map[string]any{"checks": []string{"unknown"}}, nil, "", true},
+		"common":        {map[string]any{"checks": []string{"ergocheck"}}, nil, "", true},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			plugin, err := golangci.New(tt.settings)
+			if err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			analyzers, err := plugin.BuildAnalyzers()
+			switch {
+			case tt.wantErr && err == nil:
+				t.Fatal("expect BuildAnalyzers returned an error")
+			case !tt.wantErr && err != nil:
+				t.Fatal("unexpected error:", err)
+			case err != nil:
+				return
+			}
+
+			if err := analysis.Validate(analyzers); err != nil {
+				t.Fatal("invalid analyzers:", err)
+			}
+
+			got := make([]string, len(analyzers))
+			for i, a := range analyzers {
+				got[i] = a.Name
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Error("BuildAnalyzers does not match:", diff)
+			}
+
+			if got := plugin.GetLoadMode(); got != tt.wantLoadMode {
+				t.Errorf("GetLoadMode does not match: (got, want) = (%q, %q)", got, tt.wantLoadMode)
+			}
+		})
+	}
+}
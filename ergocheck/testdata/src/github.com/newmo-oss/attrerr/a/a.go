@@ -0,0 +1,18 @@
+package a
+
+import (
+	"log/slog"
+
+	"github.com/newmo-oss/ergo"
+)
+
+func forCheckAttrErr(err error) {
+	_ = ergo.New("failed", slog.Any("err", err))                                  // want `the error passed as an attribute value of github.com/newmo-oss/ergo.New loses the error chain, it should be wrapped by ergo.Wrap`
+	_ = ergo.New("failed", slog.String("err", err.Error()))                       // want `the error passed as an attribute value of github.com/newmo-oss/ergo.New loses the error chain, it should be wrapped by ergo.Wrap`
+	_ = ergo.New("failed", slog.String("key", "value"), slog.Any("err", err))     // want `the error passed as an attribute value of github.com/newmo-oss/ergo.New loses the error chain, it should be wrapped by ergo.Wrap`
+	_ = ergo.Wrap(err, "failed", slog.Any("cause", err))                          // want `the error passed as an attribute value of github.com/newmo-oss/ergo.Wrap loses the error chain, it should be wrapped by ergo.Wrap`
+	_ = ergo.New("failed", slog.Any("err1", err), slog.Any("err2", err))          // want `the error passed as an attribute value of github.com/newmo-oss/ergo.New loses the error chain` `the error passed as an attribute value of github.com/newmo-oss/ergo.New loses the error chain`
+	_ = ergo.New("failed", slog.Any("key", "value"), slog.String("key", "value")) // OK
+	_ = ergo.New("failed", slog.Any("key", nil))                                  // OK
+	_ = ergo.Wrap(err, "failed")                                                  // OK
+}
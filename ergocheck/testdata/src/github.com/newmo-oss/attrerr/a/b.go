@@ -0,0 +1,13 @@
+package a
+
+import (
+	"log/slog"
+
+	"github.com/newmo-oss/ergo"
+)
+
+var newErr = ergo.New
+
+func forCheckAttrErrAlias(err error) {
+	_ = newErr("failed", slog.Any("err", err)) // want `the error passed as an attribute value of github.com/newmo-oss/ergo.New loses the error chain, it should be wrapped by ergo.Wrap`
+}
@@ -0,0 +1,11 @@
+package a
+
+import (
+	"log/slog"
+
+	e "github.com/newmo-oss/ergo"
+)
+
+func forCheckAttrErrRenamed(err error) {
+	_ = e.New("failed", slog.Any("err", err)) // want `the error passed as an attribute value of github.com/newmo-oss/ergo.New loses the error chain, it should be wrapped by ergo.Wrap`
+}
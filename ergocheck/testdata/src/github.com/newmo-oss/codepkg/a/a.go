@@ -0,0 +1,15 @@
+package a
+
+import (
+	"github.com/newmo-oss/codepkg/a/errcodes"
+	"github.com/newmo-oss/codepkg/b"
+	"github.com/newmo-oss/ergo"
+)
+
+var CodeA = ergo.NewCode("A", "code A")
+
+func forCheckCodePkg(err error) {
+	_ = ergo.WithCode(err, CodeA)               // OK
+	_ = ergo.WithCode(err, errcodes.CodeShared) // OK
+	_ = ergo.WithCode(err, b.CodeB)             // want `the code CodeB declared in the github.com/newmo-oss/codepkg/b package must not be attached in the github.com/newmo-oss/codepkg/a package, codes should be declared in the package itself or the allowed packages`
+}
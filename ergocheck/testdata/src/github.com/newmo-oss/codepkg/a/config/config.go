@@ -0,0 +1,12 @@
+package config
+
+import (
+	"github.com/newmo-oss/codepkg/a/errcodes"
+	"github.com/newmo-oss/codepkg/b"
+	"github.com/newmo-oss/ergo"
+)
+
+func f(err error) {
+	_ = ergo.WithCode(err, b.CodeB)             // OK
+	_ = ergo.WithCode(err, errcodes.CodeShared) // want `the code CodeShared declared in the github.com/newmo-oss/codepkg/a/errcodes package must not be attached in the github.com/newmo-oss/codepkg/a/config package, codes should be declared in the package itself or the allowed packages`
+}
@@ -0,0 +1,5 @@
+package errcodes
+
+import "github.com/newmo-oss/ergo"
+
+var CodeShared = ergo.NewCode("Shared", "shared code")
@@ -0,0 +1,5 @@
+package b
+
+import "github.com/newmo-oss/ergo"
+
+var CodeB = ergo.NewCode("B", "code B")
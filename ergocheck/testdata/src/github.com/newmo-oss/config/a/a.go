@@ -0,0 +1,25 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+)
+
+func forConfig() {
+	_ = errors.New("error") // want `warning: errors\.New must not be used in the .+ package, it should be replaced by ergo\.New`
+}
+
+func forIgnoreDirective() {
+	_ = fmt.Errorf("error") //ergocheck:ignore deprecatedfunc for test
+
+	//ergocheck:ignore nilerr,deprecatedfunc for test
+	_ = fmt.Errorf("error")
+
+	_ = fmt.Errorf("error") //ergocheck:ignore nilerr for test // want `warning: fmt\.Errorf must not be used in the .+ package, it should be replaced by ergo\.Wrap`
+
+	_ = fmt.Errorf("error") //ergocheck:ignore deprecatedfunc for test
+	_ = fmt.Errorf("error") // want `warning: fmt\.Errorf must not be used in the .+ package, it should be replaced by ergo\.Wrap`
+
+	//ergocheck:ignore deprecatedfunc	for test
+	_ = fmt.Errorf("error")
+}
@@ -0,0 +1,9 @@
+package main
+
+import (
+	"fmt"
+)
+
+func main() {
+	_ = fmt.Errorf("error") // OK - allowed by the configuration
+}
@@ -0,0 +1,9 @@
+package disabled
+
+import (
+	"github.com/newmo-oss/ergo"
+)
+
+func forConfig() {
+	_ = ergo.New("%s") // OK - formatstring is disabled
+}
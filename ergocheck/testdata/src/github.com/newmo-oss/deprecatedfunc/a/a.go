@@ -0,0 +1,21 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	_ = errors.New("error") // want `errors\.New must not be used in the .+ package, it should be replaced by ergo\.New`
+	_ = fmt.Errorf("error") // want `fmt\.Errorf must not be used in the .+ package, it should be replaced by ergo\.Wrap`
+)
+
+var _ = func() {
+	_ = errors.New("error") // want `errors\.New must not be used in the .+ package, it should be replaced by ergo\.New`
+	_ = fmt.Errorf("error") // want `fmt\.Errorf must not be used in the .+ package, it should be replaced by ergo\.Wrap`
+}
+
+func forCheckDeprecatedFunc() {
+	_ = errors.New("error") // want `errors\.New must not be used in the .+ package, it should be replaced by ergo\.New`
+	_ = fmt.Errorf("error") // want `fmt\.Errorf must not be used in the .+ package, it should be replaced by ergo\.Wrap`
+}
@@ -10,6 +10,11 @@ func Wrap(error, string, ...any) error {
 	return nil
 }
 
+// for test
+func NewSentinel(string) error {
+	return nil
+}
+
 type Code struct{}
 
 func NewCode(key, message string) Code {
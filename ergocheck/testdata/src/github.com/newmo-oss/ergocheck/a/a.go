@@ -0,0 +1,10 @@
+package a
+
+func forIgnoreDirective() {
+	//ergocheck:ignore deprecatedfunc for test
+	//ergocheck:ignore deprecatedfunc,nilerr for test
+
+	/* want `ergocheck:ignore directive must have check names and a reason such as //ergocheck:ignore <check> <reason>` */ //ergocheck:ignore deprecatedfunc
+	//ergocheck:ignore unknown for test // want `ergocheck:ignore directive has an unknown check: unknown`
+	//ergocheck:ignored the directive is not ergocheck:ignore
+}
@@ -0,0 +1,11 @@
+package all
+
+import (
+	"errors"
+	"fmt"
+)
+
+func forAnalyzer() {
+	_ = errors.New("error") // want `errors\.New must not be used in the .+ package, it should be replaced by ergo\.New`
+	_ = fmt.Errorf("error") // want `fmt\.Errorf must not be used in the .+ package, it should be replaced by ergo\.Wrap`
+}
@@ -0,0 +1,85 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+)
+
+type MyError struct{}
+
+func (*MyError) Error() string { return "my error" }
+
+func (e *MyError) Is(target error) bool {
+	_, ok := target.(*MyError) // OK
+	return ok
+}
+
+func forCheckTypeAssert(err error) {
+	if _, ok := err.(*MyError); ok { // want `type assertion on an error must not be used because the error may be wrapped, errors.As should be used instead`
+		return
+	}
+	_ = err.(*MyError) // want `type assertion on an error must not be used because the error may be wrapped, errors.As should be used instead`
+
+	if _, ok := err.(interface{ Timeout() bool }); ok { // OK
+		return
+	}
+
+	var v any = err
+	_, _ = v.(*MyError) // OK
+}
+
+func forCheckTypeSwitch(err error) {
+	switch err.(type) { // want `type switch on an error must not be used because the error may be wrapped, errors.As should be used instead`
+	case *MyError:
+	}
+
+	switch err := err.(type) { // want `type switch on an error must not be used because the error may be wrapped, errors.As should be used instead`
+	case *MyError:
+		_ = err
+	}
+
+	switch err.(type) { // want `type switch on an error must not be used because the error may be wrapped, errors.As should be used instead`
+	case interface{ Timeout() bool }, *MyError:
+	}
+
+	switch err.(type) { // OK
+	case nil:
+	case interface{ Timeout() bool }:
+	case fmt.Stringer:
+	}
+}
+
+func forCheckUnwrap(err error) {
+	if _, ok := errors.Unwrap(err).(*MyError); ok { // OK
+		return
+	}
+
+	parent := errors.Unwrap(err)
+	switch parent.(type) { // OK
+	case *MyError:
+	}
+}
+
+func forCheckErrorString(err error) {
+	if err.Error() == "not found" { // want `comparing the message of an error with a string literal must not be used, sentinel errors or codes should be used instead`
+		return
+	}
+
+	if "not found" != err.Error() { // want `comparing the message of an error with a string literal must not be used, sentinel errors or codes should be used instead`
+		return
+	}
+
+	var myErr *MyError
+	if myErr.Error() == "my error" { // want `comparing the message of an error with a string literal must not be used, sentinel errors or codes should be used instead`
+		return
+	}
+
+	switch err.Error() {
+	case "a": // want `comparing the message of an error with a string literal must not be used, sentinel errors or codes should be used instead`
+	}
+
+	msg := fmt.Sprint("a")
+	if err.Error() == msg { // OK
+		return
+	}
+}
@@ -0,0 +1,64 @@
+package a
+
+import (
+	"errors"
+
+	"github.com/newmo-oss/ergo"
+)
+
+var ForPhi bool
+
+var (
+	ErrVarNew  = ergo.New("error")
+	ErrVarWrap = ergo.Wrap(ErrVarNew, "wrap")
+)
+
+var ErrSentinel = ergo.NewSentinel("sentinel")
+
+func forCheckErrComparison(err error) {
+	_ = err == ErrSentinel // want `the comparison with the sentinel error ErrSentinel by == should be replaced by errors.Is`
+	_ = ErrSentinel != err // want `the comparison with the sentinel error ErrSentinel by != should be replaced by errors.Is`
+	_ = err == nil         // OK
+	_ = err != nil         // OK
+
+	_ = errors.Is(err, ErrSentinel) // OK
+	// package variables are checked by varinit
+	_ = errors.Is(err, ErrVarNew)  // OK
+	_ = errors.Is(err, ErrVarWrap) // OK
+
+	_ = errors.Is(err, ergo.New("error"))       // want `the 2nd argument of errors.Is is freshly created by github.com/newmo-oss/ergo.New and is not wrapped by any error, thus it never matches, use a sentinel error created by ergo.NewSentinel`
+	_ = errors.Is(err, ergo.Wrap(err, "error")) // want `the 2nd argument of errors.Is is freshly created by github.com/newmo-oss/ergo.Wrap and is not wrapped by any error, thus it never matches, use a sentinel error created by ergo.NewSentinel`
+
+	fresh := ergo.New("error")
+	_ = errors.Is(err, fresh) // want `the 2nd argument of errors.Is is freshly created by github.com/newmo-oss/ergo.New and is not wrapped by any error, thus it never matches, use a sentinel error created by ergo.NewSentinel`
+	_ = err == fresh          // want `the error freshly created by github.com/newmo-oss/ergo.New is not wrapped by any error, thus the comparison never matches, compare with a sentinel error created by ergo.NewSentinel`
+
+	phi := ergo.New("error")
+	if ForPhi {
+		phi = ergo.New("other")
+	}
+	_ = errors.Is(err, phi) // want `the 2nd argument of errors.Is is freshly created by github.com/newmo-oss/ergo.New and is not wrapped by any error, thus it never matches, use a sentinel error created by ergo.NewSentinel`
+}
+
+func forCheckWrapped() {
+	target := ergo.New("error")
+	err := ergo.Wrap(target, "wrap")
+	_ = errors.Is(err, target) // OK
+	_ = err == target          // OK
+}
+
+func forCheckPhiWithSentinel(err error) {
+	target := ergo.New("error")
+	if ForPhi {
+		target = ErrSentinel
+	}
+	_ = errors.Is(err, target) // OK
+}
+
+func forCheckReturned(err error) error {
+	target := ergo.New("error")
+	if errors.Is(err, target) { // OK
+		return nil
+	}
+	return target
+}
@@ -0,0 +1,49 @@
+package a
+
+import "github.com/newmo-oss/ergo"
+
+var (
+	newErr  = ergo.New
+	wrapErr = ergo.Wrap
+
+	// reassigned in forCheckReassigned
+	reassigned = ergo.New
+)
+
+func newError[T any](msg string, args ...any) error {
+	return ergo.New(msg, args...)
+}
+
+func wrapError[E error](err E, msg string, args ...any) error {
+	return ergo.Wrap(err, msg, args...)
+}
+
+func notForward[T any](msg string) error {
+	return ergo.New("error: " + msg)
+}
+
+func forCheckLocalAlias(err error) {
+	f := ergo.New
+	_ = f("%s") // want `the message of github.com/newmo-oss/ergo.New must not be format string such as "xxxx %s": "%s"`
+
+	w := wrapErr
+	_ = w(err, "%s") // want `the message of github.com/newmo-oss/ergo.Wrap must not be format string such as "xxxx %s": "%s"`
+}
+
+func forCheckPackageAlias(err error) {
+	_ = newErr("%s")       // want `the message of github.com/newmo-oss/ergo.New must not be format string such as "xxxx %s": "%s"`
+	_ = wrapErr(err, "%s") // want `the message of github.com/newmo-oss/ergo.Wrap must not be format string such as "xxxx %s": "%s"`
+}
+
+func forCheckGenerics(err error) {
+	_ = newError[int]("%s")         // want `the message of github.com/newmo-oss/ergo.New must not be format string such as "xxxx %s": "%s"`
+	_ = newError[string]("%d", 1)   // want `the message of github.com/newmo-oss/ergo.New must not be format string such as "xxxx %s": "%d"`
+	_ = wrapError(err, "%s")        // want `the message of github.com/newmo-oss/ergo.Wrap must not be format string such as "xxxx %s": "%s"`
+	_ = notForward[int]("%s")       // OK
+	_ = newError[int]("not format") // OK
+}
+
+func forCheckReassigned() {
+	reassigned = func(string, ...any) error { return nil }
+	_ = reassigned("%s") // OK
+}
@@ -0,0 +1,91 @@
+package a
+
+import (
+	"context"
+	"log"
+	"log/slog"
+
+	"github.com/newmo-oss/ergo"
+)
+
+func do() error { return nil }
+
+func forCheckSlog() error {
+	if err := do(); err != nil {
+		slog.Error("failed to do", "err", err) // want `the error is logged by log/slog.Error and also returned, it should be either logged or returned to avoid duplicated logs`
+		return err
+	}
+	return nil
+}
+
+func forCheckSlogAttr(ctx context.Context, logger *slog.Logger) error {
+	err := do()
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to do", slog.Any("err", err)) // want `the error is logged by \(\*log/slog.Logger\).ErrorContext and also returned, it should be either logged or returned to avoid duplicated logs`
+	}
+	return err
+}
+
+func forCheckSlogString() error {
+	err := do()
+	if err != nil {
+		slog.Warn("failed to do", slog.String("err", err.Error())) // want `the error is logged by log/slog.Warn and also returned, it should be either logged or returned to avoid duplicated logs`
+		return err
+	}
+	return nil
+}
+
+func forCheckLog() (int, error) {
+	if err := do(); err != nil {
+		log.Println("failed to do:", err) // want `the error is logged by log.Println and also returned, it should be either logged or returned to avoid duplicated logs`
+		return 0, err
+	}
+	return 1, nil
+}
+
+func forCheckLogOnly() {
+	if err := do(); err != nil {
+		slog.Error("failed to do", "err", err) // OK
+	}
+}
+
+func forCheckWrapped() error {
+	if err := do(); err != nil {
+		slog.Info("failed to do", "err", err) // OK
+		return ergo.Wrap(err, "failed to do")
+	}
+	return nil
+}
+
+func forCheckOtherError() error {
+	if err := do(); err != nil {
+		slog.Error("failed to do", "err", err) // OK
+	}
+	return do()
+}
+
+func forCheckRetry(n int) error {
+	for i := 0; ; i++ {
+		err := do()
+		if err == nil {
+			return nil
+		}
+
+		if i < n-1 {
+			slog.Warn("retrying", "err", err) // OK
+			continue
+		}
+		return err
+	}
+}
+
+func forCheckRetryThenReturn(n int) error {
+	var err error
+	for i := 0; i < n; i++ {
+		if err = do(); err == nil {
+			return nil
+		}
+		slog.Warn("retrying", "err", err) // want `the error is logged by log/slog.Warn and also returned, it should be either logged or returned to avoid duplicated logs`
+	}
+	return err
+}
@@ -0,0 +1,23 @@
+package a
+
+import (
+	"log/slog"
+)
+
+func forCheckMethodValue(logger *slog.Logger) error {
+	logError := logger.Error
+	if err := do(); err != nil {
+		logError("failed to do", "err", err) // want `the error is logged by \(\*log/slog.Logger\).Error and also returned, it should be either logged or returned to avoid duplicated logs`
+		return err
+	}
+	return nil
+}
+
+func forCheckFuncValue() error {
+	logError := slog.Error
+	if err := do(); err != nil {
+		logError("failed to do", "err", err) // want `the error is logged by log/slog.Error and also returned, it should be either logged or returned to avoid duplicated logs`
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package a
+
+import (
+	"errors"
+
+	"github.com/newmo-oss/ergo"
+)
+
+var (
+	ErrSentinel = ergo.NewSentinel("not found")
+	ErrStd      = errors.New("invalid argument")
+)
+
+var codeA = ergo.NewCode("A", "code A")
+
+func forCheckStyle(err error) {
+	_ = ergo.New("failed to read")                                                                      // OK
+	_ = ergo.New("HTTP request failed")                                                                 // OK
+	_ = ergo.New("ID is empty")                                                                         // OK
+	_ = ergo.New("Failed to read")                                                                      // want `the message of github.com/newmo-oss/ergo.New must start with a lowercase letter: "Failed to read"`
+	_ = ergo.Wrap(err, "Failed to read")                                                                // want `the message of github.com/newmo-oss/ergo.Wrap must start with a lowercase letter: "Failed to read"`
+	_ = ergo.New("failed to read.")                                                                     // want `the message of github.com/newmo-oss/ergo.New must not end with a period or a newline: "failed to read."`
+	_ = ergo.New("failed to read\n")                                                                    // want `the message of github.com/newmo-oss/ergo.New must not end with a period or a newline: "failed to read\\n"`
+	_ = ergo.New("error: failed to read")                                                               // want `the message of github.com/newmo-oss/ergo.New must not start with "error:": "error: failed to read"`
+	_ = ergo.New("Failed: read")                                                                        // want `the message of github.com/newmo-oss/ergo.New must start with a lowercase letter: "Failed: read"`
+	_ = ergo.Wrap(err, "failed: read")                                                                  // want `the message of github.com/newmo-oss/ergo.Wrap must not start with "failed:": "failed: read"`
+	_ = ergo.New("")                                                                                    // OK
+	_ = ergo.New("failed to read the configuration file of the application because the file is broken") // want `the message of github.com/newmo-oss/ergo.New must not be longer than 80 characters: ".+"`
+}
+
+func forCheckStutter(err error, msg string) {
+	_ = ergo.Wrap(ergo.New("failed to read"), "failed to read")            // want `the message of github.com/newmo-oss/ergo.Wrap stutters with the message of the wrapped error: "failed to read": "failed to read"`
+	_ = ergo.Wrap(ergo.New("failed to read"), "failed to")                 // want `the message of github.com/newmo-oss/ergo.Wrap stutters with the message of the wrapped error: "failed to": "failed to read"`
+	_ = ergo.Wrap(ergo.New("failed to read"), "failed to read the config") // want `the message of github.com/newmo-oss/ergo.Wrap stutters with the message of the wrapped error: "failed to read the config": "failed to read"`
+	_ = ergo.Wrap(ErrSentinel, "not found: user")                          // want `the message of github.com/newmo-oss/ergo.Wrap stutters with the message of the wrapped error: "not found: user": "not found"`
+	_ = ergo.Wrap(ErrStd, "invalid argument")                              // want `the message of github.com/newmo-oss/ergo.Wrap stutters with the message of the wrapped error: "invalid argument": "invalid argument"`
+	_ = ergo.Wrap(ergo.WithCode(ErrSentinel, codeA), "not found")          // want `the message of github.com/newmo-oss/ergo.Wrap stutters with the message of the wrapped error: "not found": "not found"`
+	_ = ergo.Wrap(ergo.New("failed to read"), "failed to load the config") // OK
+	_ = ergo.Wrap(ErrSentinel, "failed to find the user")                  // OK
+	_ = ergo.Wrap(ErrSentinel, "user not found")                           // OK
+	_ = ergo.Wrap(ergo.New("failed to read"), "failed to reads")           // OK
+	_ = ergo.Wrap(ergo.New(msg), "failed to read")                         // OK
+	_ = ergo.Wrap(err, "failed to read")                                   // OK
+}
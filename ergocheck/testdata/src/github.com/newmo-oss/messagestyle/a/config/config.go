@@ -0,0 +1,9 @@
+package config
+
+import "github.com/newmo-oss/ergo"
+
+func f() {
+	_ = ergo.New("error: read")                      // OK
+	_ = ergo.New("oops: failed to read")             // want `the message of github.com/newmo-oss/ergo.New must not start with "oops:": "oops: failed to read"`
+	_ = ergo.New("failed to read the configuration") // want `the message of github.com/newmo-oss/ergo.New must not be longer than 20 characters: "failed to read the configuration"`
+}
@@ -0,0 +1,26 @@
+package a
+
+import (
+	"github.com/newmo-oss/ergo"
+)
+
+const codeKey = "CodeConst"
+
+var codeKeyVar = "CodeVar"
+
+var (
+	_ = ergo.NewCode("Code", "code")        // OK
+	_ = ergo.NewCode(codeKey, "code")       // OK
+	_ = ergo.NewCode("Code", "duplicated")  // want `the key "Code" of github.com/newmo-oss/ergo.NewCode is duplicated with another code`
+	_ = ergo.NewCode(codeKeyVar, "code")    // want `the key of github.com/newmo-oss/ergo.NewCode must be a constant string`
+	_ = (ergo.NewCode("CodeParen", "code")) // OK
+	_ = func() ergo.Code {
+		return ergo.NewCode("CodeInFuncLit", "code") // want `github.com/newmo-oss/ergo.NewCode must be called only in package variable initialization`
+	}()
+)
+
+func forCheckNewCode() {
+	_ = ergo.NewCode("CodeInFunc", "code")        // want `github.com/newmo-oss/ergo.NewCode must be called only in package variable initialization`
+	var code = ergo.NewCode("CodeInFunc", "code") // want `github.com/newmo-oss/ergo.NewCode must be called only in package variable initialization`
+	_ = code
+}
@@ -0,0 +1,208 @@
+package a
+
+import (
+	"errors"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/newmo-oss/nilerr/b"
+)
+
+var ForPhi bool
+
+var code = ergo.NewCode("code", "code")
+
+func forCheckNilErr() {
+	_ = ergo.Wrap(nil, "") // want `The 1st argument of github.com/newmo-oss/ergo.Wrap must not be nil`
+	code := ergo.NewCode("coe", "code")
+	_ = ergo.WithCode(nil, code) // want `The 1st argument of github.com/newmo-oss/ergo.WithCode must not be nil`
+
+	{
+		var err error
+		if ForPhi {
+			err = ergo.New("error")
+		}
+		_ = ergo.Wrap(err, "")       // want `The 1st argument of github.com/newmo-oss/ergo.Wrap must not be nil`
+		_ = ergo.WithCode(err, code) // want `The 1st argument of github.com/newmo-oss/ergo.WithCode must not be nil`
+
+		if err != nil {
+			// noop
+		}
+
+		if err != nil {
+			_ = ergo.Wrap(err, "")       // OK
+			_ = ergo.WithCode(err, code) // OK
+		}
+
+		if nil != err {
+			_ = ergo.Wrap(err, "")       // OK
+			_ = ergo.WithCode(err, code) // OK
+		}
+	}
+}
+
+func forCheckNilErrFacts(cond bool) {
+	_ = ergo.Wrap(b.AlwaysNil(), "")             // want `The 1st argument of github.com/newmo-oss/ergo.Wrap must not be nil, github.com/newmo-oss/nilerr/b.AlwaysNil always returns nil error`
+	_ = ergo.Wrap(b.PossiblyNil(cond), "")       // want `The 1st argument of github.com/newmo-oss/ergo.Wrap may be nil because github.com/newmo-oss/nilerr/b.PossiblyNil may return nil error, it should be guarded by a nil check`
+	_ = ergo.Wrap(b.NotNil(), "")                // OK
+	_ = ergo.Wrap(localAlwaysNil(), "")          // want `The 1st argument of github.com/newmo-oss/ergo.Wrap must not be nil, github.com/newmo-oss/nilerr/a.localAlwaysNil always returns nil error`
+	_ = ergo.WithCode(b.PossiblyNil(cond), code) // want `The 1st argument of github.com/newmo-oss/ergo.WithCode may be nil because github.com/newmo-oss/nilerr/b.PossiblyNil may return nil error, it should be guarded by a nil check`
+
+	if err := b.PossiblyNil(cond); err != nil {
+		_ = ergo.Wrap(err, "") // OK
+	}
+
+	if _, err := b.PossiblyNilWithValue(cond); err != nil {
+		_ = ergo.Wrap(err, "") // OK
+	}
+
+	_, err := b.PossiblyNilWithValue(cond)
+	_ = ergo.Wrap(err, "") // want `The 1st argument of github.com/newmo-oss/ergo.Wrap may be nil because github.com/newmo-oss/nilerr/b.PossiblyNilWithValue may return nil error, it should be guarded by a nil check`
+
+	_ = b.Wrap(nil)                 // want `The 1st argument of github.com/newmo-oss/nilerr/b.Wrap must not be nil`
+	_ = b.WrapWrap(nil)             // want `The 1st argument of github.com/newmo-oss/nilerr/b.WrapWrap must not be nil`
+	_ = b.WrapGuarded(nil)          // OK
+	_ = b.T{}.Wrap(nil)             // want `The 1st argument of \(github.com/newmo-oss/nilerr/b.T\).Wrap must not be nil`
+	_ = b.Wrap(b.PossiblyNil(cond)) // want `The 1st argument of github.com/newmo-oss/nilerr/b.Wrap may be nil because github.com/newmo-oss/nilerr/b.PossiblyNil may return nil error, it should be guarded by a nil check`
+	_ = b.Wrap(b.NotNil())          // OK
+	_ = localWrap(nil)              // want `The 1st argument of github.com/newmo-oss/nilerr/a.localWrap must not be nil`
+}
+
+func localAlwaysNil() error { // want localAlwaysNil:"alwaysNil"
+	return nil
+}
+
+func localWrap(err error) error { // want localWrap:`wrapParams\(0\)`
+	return ergo.Wrap(err, "wrap")
+}
+
+var errSentinel = ergo.NewSentinel("sentinel")
+
+type myError struct{}
+
+func (*myError) Error() string { return "error" }
+
+func maybeErr() error { // want maybeErr:"possiblyNil"
+	var err error
+	if ForPhi {
+		err = ergo.New("error")
+	}
+	return err
+}
+
+func forCheckNilGuardErrorsIs() {
+	var err error
+	if ForPhi {
+		err = ergo.New("error")
+	}
+
+	if errors.Is(err, errSentinel) {
+		_ = ergo.Wrap(err, "") // OK
+	} else {
+		_ = ergo.Wrap(err, "") // want `The 1st argument of github.com/newmo-oss/ergo.Wrap must not be nil`
+	}
+
+	if errors.Is(err, nil) {
+		_ = ergo.Wrap(err, "") // want `The 1st argument of github.com/newmo-oss/ergo.Wrap must not be nil`
+	}
+}
+
+func forCheckNilGuardErrorsAs() {
+	var err error
+	if ForPhi {
+		err = ergo.New("error")
+	}
+
+	var target *myError
+	if errors.As(err, &target) {
+		_ = ergo.Wrap(err, "") // OK
+	}
+}
+
+func forCheckNilGuardSwitch(ok bool) {
+	var err error
+	if ForPhi {
+		err = ergo.New("error")
+	}
+
+	switch {
+	case err != nil:
+		_ = ergo.Wrap(err, "") // OK
+	}
+
+	switch {
+	case err == nil:
+	default:
+		_ = ergo.Wrap(err, "") // OK
+	}
+
+	switch {
+	case err != nil:
+		_ = ergo.Wrap(err, "") // OK
+		fallthrough
+	case ok:
+		_ = ergo.Wrap(err, "") // want `The 1st argument of github.com/newmo-oss/ergo.Wrap must not be nil`
+	}
+}
+
+func forCheckNilGuardEarlyReturn() error { // want forCheckNilGuardEarlyReturn:"possiblyNil"
+	var err error
+	if ForPhi {
+		err = ergo.New("error")
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	return ergo.Wrap(err, "") // OK
+}
+
+func forCheckNilGuardCompound(ok bool) {
+	var err error
+	if ForPhi {
+		err = ergo.New("error")
+	}
+
+	if ok && err != nil {
+		_ = ergo.Wrap(err, "") // OK
+	}
+
+	if err != nil && ok {
+		_ = ergo.Wrap(err, "") // OK
+	}
+
+	if err != nil || ok {
+		_ = ergo.Wrap(err, "") // want `The 1st argument of github.com/newmo-oss/ergo.Wrap must not be nil`
+	}
+
+	if !(err == nil) {
+		_ = ergo.Wrap(err, "") // OK
+	}
+}
+
+func forCheckNilGuardElse() {
+	var err error
+	if ForPhi {
+		err = ergo.New("error")
+	}
+
+	if err != nil {
+		// noop
+	} else {
+		_ = ergo.Wrap(err, "") // want `The 1st argument of github.com/newmo-oss/ergo.Wrap must not be nil`
+	}
+}
+
+func forCheckNilGuardFacts(ok bool) {
+	if err := maybeErr(); ok && err != nil {
+		_ = ergo.Wrap(err, "") // OK
+	}
+
+	if err := maybeErr(); errors.Is(err, errSentinel) {
+		_ = ergo.Wrap(err, "") // OK
+	}
+
+	if err := maybeErr(); err != nil || ok {
+		_ = ergo.Wrap(err, "") // want `The 1st argument of github.com/newmo-oss/ergo.Wrap may be nil because github.com/newmo-oss/nilerr/a.maybeErr may return nil error, it should be guarded by a nil check`
+	}
+}
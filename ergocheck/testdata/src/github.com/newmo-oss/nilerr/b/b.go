@@ -0,0 +1,45 @@
+package b
+
+import (
+	"github.com/newmo-oss/ergo"
+)
+
+func AlwaysNil() error {
+	return nil
+}
+
+func PossiblyNil(cond bool) error {
+	if cond {
+		return ergo.New("error")
+	}
+	return nil
+}
+
+func PossiblyNilWithValue(cond bool) (int, error) {
+	return 0, PossiblyNil(cond)
+}
+
+func NotNil() error {
+	return ergo.New("error")
+}
+
+func Wrap(err error) error {
+	return ergo.Wrap(err, "wrap")
+}
+
+func WrapGuarded(err error) error {
+	if err != nil {
+		return ergo.Wrap(err, "wrap")
+	}
+	return nil
+}
+
+func WrapWrap(err error) error {
+	return Wrap(err)
+}
+
+type T struct{}
+
+func (T) Wrap(err error) error {
+	return ergo.Wrap(err, "wrap")
+}
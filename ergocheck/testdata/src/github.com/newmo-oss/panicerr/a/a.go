@@ -0,0 +1,45 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/newmo-oss/ergo"
+)
+
+type myError struct{}
+
+func (myError) Error() string { return "my error" }
+
+func forCheckPanicErr(err error) {
+	if err != nil {
+		panic(err) // want `panic with an error must not be used in the github.com/newmo-oss/panicerr/a package, the error should be returned`
+	}
+
+	panic(ergo.New("unexpected")) // want `panic with an error must not be used in the github.com/newmo-oss/panicerr/a package, the error should be returned`
+}
+
+func forCheckPanicErrConcrete() {
+	panic(myError{}) // want `panic with an error must not be used in the github.com/newmo-oss/panicerr/a package, the error should be returned`
+}
+
+func forCheckPanicErrStd() {
+	panic(fmt.Errorf("unexpected")) // want `panic with an error must not be used in the github.com/newmo-oss/panicerr/a package, the error should be returned`
+}
+
+func forCheckPanicString(err error) {
+	switch {
+	case err == nil:
+		panic("unexpected") // OK
+	case errors.Is(err, err):
+		panic(err.Error()) // OK
+	default:
+		panic(fmt.Sprint(err)) // OK
+	}
+}
+
+func MustDo(err error) {
+	if err != nil {
+		panic(err) // OK
+	}
+}
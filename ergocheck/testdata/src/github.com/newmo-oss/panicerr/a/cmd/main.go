@@ -0,0 +1,7 @@
+package main
+
+import "github.com/newmo-oss/ergo"
+
+func main() {
+	panic(ergo.New("unexpected")) // OK
+}
@@ -0,0 +1,49 @@
+package a
+
+import (
+	"log/slog"
+
+	"github.com/newmo-oss/ergo"
+)
+
+var ForPhi bool
+
+var (
+	codeA = ergo.NewCode("A", "code A")
+	codeB = ergo.NewCode("B", "code B")
+)
+
+func forCheckEmptyMessage(err error, attrs []any) {
+	_ = ergo.Wrap(err, "")                        // want `github.com/newmo-oss/ergo.Wrap with an empty message and no attributes is redundant, the error should be returned as it is`
+	_ = ergo.Wrap(err, "", slog.String("k", "v")) // OK
+	_ = ergo.Wrap(err, "", attrs...)              // OK
+	_ = ergo.Wrap(err, "wrap")                    // OK
+}
+
+func forCheckDoubleWrap(err error) error {
+	err = ergo.Wrap(err, "a")
+	return ergo.Wrap(err, "b") // want `the error is wrapped by github.com/newmo-oss/ergo.Wrap twice in the function, the wraps should be merged into one`
+}
+
+func forCheckDoubleWrapNested(err error) error {
+	return ergo.Wrap(ergo.Wrap(err, "a"), "b") // want `the error is wrapped by github.com/newmo-oss/ergo.Wrap twice in the function, the wraps should be merged into one`
+}
+
+func forCheckDoubleWrapPhi(err error) error {
+	if ForPhi {
+		err = ergo.Wrap(err, "a")
+	}
+	return ergo.Wrap(err, "b") // OK
+}
+
+func forCheckWithCode(err error) {
+	coded := ergo.WithCode(err, codeA)
+	_ = ergo.WithCode(coded, codeB)                    // want `the error already has a code attached by github.com/newmo-oss/ergo.WithCode, the code is hidden by the new code`
+	_ = ergo.WithCode(ergo.Wrap(coded, "wrap"), codeB) // want `the error already has a code attached by github.com/newmo-oss/ergo.WithCode, the code is hidden by the new code`
+	_ = ergo.WithCode(err, codeB)                      // OK
+
+	if ForPhi {
+		err = ergo.WithCode(err, codeA)
+	}
+	_ = ergo.WithCode(err, codeB) // OK
+}
@@ -0,0 +1,30 @@
+package a
+
+import "github.com/newmo-oss/ergo"
+
+var (
+	ErrNotFound = ergo.NewSentinel("not found")   // OK
+	errInternal = ergo.NewSentinel("internal")    // OK
+	NotFound    = ergo.NewSentinel("not found")   // want `the sentinel error NotFound should be named with the prefix Err such as ErrNotFound`
+	invalid     = ergo.NewSentinel("invalid")     // want `the sentinel error invalid should be named with the prefix Err such as ErrInvalid`
+	_           = ergo.NewSentinel("placeholder") // OK
+)
+
+var (
+	ErrA, ErrB = ergo.NewSentinel("a"), ergo.NewSentinel("b") // OK
+	ErrWrapped = wrap(ergo.NewSentinel("wrapped"))            // want `github.com/newmo-oss/ergo.NewSentinel must be called only in package variable initialization, a sentinel error created at runtime cannot be compared by errors.Is`
+)
+
+func wrap(err error) error { return err }
+
+func forCheckRuntime() error {
+	return ergo.NewSentinel("runtime") // want `github.com/newmo-oss/ergo.NewSentinel must be called only in package variable initialization, a sentinel error created at runtime cannot be compared by errors.Is`
+}
+
+func forCheckReassign() {
+	ErrNotFound = ergo.NewSentinel("missing") // want `the exported sentinel error ErrNotFound must not be reassigned` `github.com/newmo-oss/ergo.NewSentinel must be called only in package variable initialization, a sentinel error created at runtime cannot be compared by errors.Is`
+	errInternal = nil                         // OK
+	ErrA, ErrB = ErrB, ErrA                   // want `the exported sentinel error ErrA must not be reassigned` `the exported sentinel error ErrB must not be reassigned`
+	err := ErrNotFound                        // OK
+	_ = err
+}
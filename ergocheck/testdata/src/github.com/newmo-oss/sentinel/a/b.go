@@ -0,0 +1,7 @@
+package a
+
+import "errors"
+
+var ErrB1 = errors.New("b1") // want `errors.New must not be used for the sentinel error ErrB1, it should be replaced by ergo.NewSentinel`
+
+var ErrB2 = errors.New("b2") // want `errors.New must not be used for the sentinel error ErrB2, it should be replaced by ergo.NewSentinel`
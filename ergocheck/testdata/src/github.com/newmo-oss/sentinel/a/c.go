@@ -0,0 +1,11 @@
+package a
+
+import (
+	"errors"
+)
+
+var ErrC = errors.New("c") // want `errors.New must not be used for the sentinel error ErrC, it should be replaced by ergo.NewSentinel`
+
+func isC(err error) bool {
+	return errors.Is(err, ErrC)
+}
@@ -0,0 +1,11 @@
+package a
+
+import (
+	"errors"
+
+	errs "github.com/newmo-oss/ergo"
+)
+
+var ErrD = errors.New("d") // want `errors.New must not be used for the sentinel error ErrD, it should be replaced by ergo.NewSentinel`
+
+var ErrD2 = errs.NewSentinel("d2") // OK
@@ -0,0 +1,10 @@
+package a
+
+import (
+	"github.com/newmo-oss/ergo"
+)
+
+var (
+	ErrVarNew  = ergo.New("error")            // want `ergo\.New must not be used in package variable initilization, it should be replaced by ergo\.NewSentinel`
+	ErrVarWrap = ergo.Wrap(ErrVarNew, "wrap") // want `ergo\.Wrap must not be used in package variable initilization, it should be replaced by errors\.Join`
+)
@@ -0,0 +1,17 @@
+package a
+
+import (
+	"github.com/newmo-oss/ergo"
+)
+
+var newErr = ergo.New
+
+func newError[T any](msg string, args ...any) error {
+	return ergo.New(msg, args...)
+}
+
+var (
+	ErrAlias   = newErr("error")         // want `ergo\.New must not be used in package variable initilization, it should be replaced by ergo\.NewSentinel`
+	ErrGeneric = newError[int]("error")  // want `ergo\.New must not be used in package variable initilization, it should be replaced by ergo\.NewSentinel`
+	ErrInfer   = (newError[string])("e") // want `ergo\.New must not be used in package variable initilization, it should be replaced by ergo\.NewSentinel`
+)
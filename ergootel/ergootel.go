@@ -0,0 +1,159 @@
+// Package ergootel records errors created by [github.com/newmo-oss/ergo] on OpenTelemetry spans.
+//
+// Unlike [trace.Span.RecordError], the code, the attributes and the stack trace of the error are recorded
+// as the attributes of the exception event.
+//
+//	if err != nil {
+//		ergootel.RecordError(span, err)
+//	}
+package ergootel
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/newmo-oss/ergo/report"
+)
+
+// keys of the exception event which are defined by the semantic conventions.
+const (
+	eventName           = "exception"
+	keyExceptionType    = attribute.Key("exception.type")
+	keyExceptionMessage = attribute.Key("exception.message")
+	keyExceptionStack   = attribute.Key("exception.stacktrace")
+)
+
+// RecordError records the error on the span as an exception event and sets the status of the span to Error.
+// The event has the following attributes:
+//
+//   - exception.type: the package path and the key of the code of the error such as "example.com/repo.NotFound",
+//     or the Go type of the error if it has no code
+//   - exception.message: the message of the error
+//   - exception.stacktrace: the stack trace of the error obtained by ergo.StackTraceOf
+//   - the attributes of the error obtained by ergo.AttrsAll
+//
+// If err is nil, RecordError does nothing.
+func RecordError(span trace.Span, err error, opts ...trace.EventOption) {
+	if err == nil {
+		return
+	}
+	recordEvent(span, report.FromError(err), opts...)
+}
+
+var _ report.Reporter = Reporter{}
+
+// Reporter is a [report.Reporter] which records events on the spans in the contexts as same as [RecordError].
+type Reporter struct{}
+
+// Report implements [report.Reporter].
+func (Reporter) Report(ctx context.Context, event *report.Event) error {
+	if event != nil {
+		recordEvent(trace.SpanFromContext(ctx), event)
+	}
+	return nil
+}
+
+func recordEvent(span trace.Span, event *report.Event, opts ...trace.EventOption) {
+	if !span.IsRecording() {
+		return
+	}
+
+	attrs := []attribute.KeyValue{
+		keyExceptionType.String(exceptionType(event)),
+		keyExceptionMessage.String(event.Message),
+	}
+
+	if st := stackTrace(event); st != "" {
+		attrs = append(attrs, keyExceptionStack.String(st))
+	}
+
+	for _, attr := range event.Attrs {
+		attrs = appendAttr(attrs, "", attr)
+	}
+
+	opts = append([]trace.EventOption{trace.WithAttributes(attrs...)}, opts...)
+	span.AddEvent(eventName, opts...)
+	span.SetStatus(codes.Error, event.Message)
+}
+
+func exceptionType(event *report.Event) string {
+	if !event.Code.IsZero() {
+		return event.Code.PkgPath() + "." + event.Code.Key()
+	}
+
+	// the type of the errors created by ergo is less informative than the types of the errors wrapped by them
+	for _, exception := range event.Exceptions {
+		if exception.Type != report.ErgoType {
+			return exception.Type
+		}
+	}
+
+	if len(event.Exceptions) > 0 {
+		return report.ErgoType
+	}
+
+	return ""
+}
+
+// stackTrace formats the stack trace of the event in the same format as [runtime/debug.Stack].
+func stackTrace(event *report.Event) string {
+	// the innermost stack trace is the one obtained by ergo.StackTraceOf
+	var frames []*report.Frame
+	for _, exception := range event.Exceptions {
+		if len(exception.Frames) > 0 {
+			frames = exception.Frames
+		}
+	}
+
+	var sb strings.Builder
+	for _, frame := range frames {
+		fmt.Fprintf(&sb, "%s.%s\n\t%s:%d\n", frame.Package, frame.Function, frame.File, frame.Line)
+	}
+	return sb.String()
+}
+
+// appendAttr converts the slog attribute to OpenTelemetry attributes.
+// The attributes in a group are flattened with the keys joined by ".".
+func appendAttr(attrs []attribute.KeyValue, prefix string, attr slog.Attr) []attribute.KeyValue {
+	value := attr.Value.Resolve()
+
+	key := attr.Key
+	if prefix != "" {
+		key = prefix + "." + key
+	}
+
+	switch value.Kind() {
+	case slog.KindString:
+		return append(attrs, attribute.String(key, value.String()))
+	case slog.KindInt64:
+		return append(attrs, attribute.Int64(key, value.Int64()))
+	case slog.KindUint64:
+		return append(attrs, attribute.String(key, value.String()))
+	case slog.KindFloat64:
+		return append(attrs, attribute.Float64(key, value.Float64()))
+	case slog.KindBool:
+		return append(attrs, attribute.Bool(key, value.Bool()))
+	case slog.KindDuration:
+		return append(attrs, attribute.String(key, value.Duration().String()))
+	case slog.KindTime:
+		return append(attrs, attribute.String(key, value.Time().Format(time.RFC3339Nano)))
+	case slog.KindGroup:
+		if attr.Key == "" {
+			// the attributes of a group with an empty key are inlined as same as slog
+			key = prefix
+		}
+		for _, attr := range value.Group() {
+			attrs = appendAttr(attrs, key, attr)
+		}
+		return attrs
+	default:
+		return append(attrs, attribute.String(key, value.String()))
+	}
+}
@@ -0,0 +1,162 @@
+package ergootel_test
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/newmo-oss/ergo/ergootel"
+	"github.com/newmo-oss/ergo/report"
+)
+
+var codeA = ergo.NewCode("A", "code A message")
+
+func newError() error {
+	err := ergo.New("not found", slog.String("id", "id1"), slog.Group("req", slog.Int("size", 10), slog.Bool("retry", true)))
+	err = ergo.WithCode(err, codeA)
+	return ergo.Wrap(err, "failed to find", slog.Float64("rate", 0.5), slog.Duration("elapsed", time.Second))
+}
+
+func TestRecordError(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		err    error
+		record func(t *testing.T, ctx context.Context, span trace.Span, err error)
+
+		wantType  string
+		wantAttrs []attribute.KeyValue
+	}{
+		"RecordError": {
+			newError(),
+			func(_ *testing.T, _ context.Context, span trace.Span, err error) { ergootel.RecordError(span, err) },
+			"github.com/newmo-oss/ergo/ergootel_test.A",
+			[]attribute.KeyValue{
+				attribute.Float64("rate", 0.5),
+				attribute.String("elapsed", "1s"),
+				attribute.String("id", "id1"),
+				attribute.Int64("req.size", 10),
+				attribute.Bool("req.retry", true),
+			},
+		},
+		"Reporter": {
+			newError(),
+			func(t *testing.T, ctx context.Context, _ trace.Span, err error) {
+				if err := (ergootel.Reporter{}).Report(ctx, report.FromError(err)); err != nil {
+					t.Fatal("unexpected error:", err)
+				}
+			},
+			"github.com/newmo-oss/ergo/ergootel_test.A",
+			[]attribute.KeyValue{
+				attribute.Float64("rate", 0.5),
+				attribute.String("elapsed", "1s"),
+				attribute.String("id", "id1"),
+				attribute.Int64("req.size", 10),
+				attribute.Bool("req.retry", true),
+			},
+		},
+		"no code": {
+			ergo.New("error"),
+			func(_ *testing.T, _ context.Context, span trace.Span, err error) { ergootel.RecordError(span, err) },
+			report.ErgoType,
+			nil,
+		},
+		"wrapped": {
+			ergo.Wrap(&fs.PathError{Op: "open", Path: "a.txt", Err: fs.ErrNotExist}, "failed to open"),
+			func(_ *testing.T, _ context.Context, span trace.Span, err error) { ergootel.RecordError(span, err) },
+			"*fs.PathError",
+			nil,
+		},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			exporter := tracetest.NewInMemoryExporter()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			ctx, span := provider.Tracer("test").Start(context.Background(), "span")
+			tt.record(t, ctx, span, tt.err)
+			span.End()
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("the number of spans does not match: (got, want) = (%d, %d)", len(spans), 1)
+			}
+
+			if got, want := spans[0].Status, (sdktrace.Status{Code: codes.Error, Description: tt.err.Error()}); got != want {
+				t.Errorf("Status does not match: (got, want) = (%v, %v)", got, want)
+			}
+
+			events := spans[0].Events
+			if len(events) != 1 {
+				t.Fatalf("the number of events does not match: (got, want) = (%d, %d)", len(events), 1)
+			}
+
+			if got, want := events[0].Name, "exception"; got != want {
+				t.Errorf("event name does not match: (got, want) = (%q, %q)", got, want)
+			}
+
+			attrs := make(map[attribute.Key]attribute.Value)
+			var others []attribute.KeyValue
+			for _, kv := range events[0].Attributes {
+				switch kv.Key {
+				case "exception.type", "exception.message", "exception.stacktrace":
+					attrs[kv.Key] = kv.Value
+				default:
+					others = append(others, kv)
+				}
+			}
+
+			if got := attrs["exception.type"].AsString(); got != tt.wantType {
+				t.Errorf("exception.type does not match: (got, want) = (%q, %q)", got, tt.wantType)
+			}
+
+			if got, want := attrs["exception.message"].AsString(), tt.err.Error(); got != want {
+				t.Errorf("exception.message does not match: (got, want) = (%q, %q)", got, want)
+			}
+
+			if got, want := attrs["exception.stacktrace"].AsString(), "github.com/newmo-oss/ergo/ergootel_test."; !strings.HasPrefix(got, want) {
+				t.Errorf("exception.stacktrace must start with %q but got %q", want, got)
+			}
+
+			if diff := cmp.Diff(tt.wantAttrs, others, cmp.Comparer(func(x, y attribute.KeyValue) bool { return x == y })); diff != "" {
+				t.Errorf("attributes do not match (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRecordErrorNil(t *testing.T) {
+	t.Parallel()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	_, span := provider.Tracer("test").Start(context.Background(), "span")
+	ergootel.RecordError(span, nil)
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("the number of spans does not match: (got, want) = (%d, %d)", len(spans), 1)
+	}
+
+	if got := spans[0].Status.Code; got != codes.Unset {
+		t.Errorf("Status must be unset but got %v", got)
+	}
+
+	if got := len(spans[0].Events); got != 0 {
+		t.Errorf("the number of events must be 0 but got %d", got)
+	}
+}
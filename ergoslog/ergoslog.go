@@ -0,0 +1,196 @@
+// Package ergoslog provides a [slog.Handler] which expands errors created by [github.com/newmo-oss/ergo] in log records.
+//
+//	logger := slog.New(ergoslog.NewHandler(slog.NewJSONHandler(os.Stderr, nil), &ergoslog.Options{
+//		LiftKeys: []string{"user_id"},
+//	}))
+//	logger.Error("failed to find the user", "err", err)
+package ergoslog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/newmo-oss/ergo"
+)
+
+// keys of the attributes which an error is expanded into.
+const (
+	KeyMessage = "msg"
+	KeyCode    = "code"
+	KeyAttrs   = "attrs"
+	KeyStack   = "stack"
+)
+
+// Options are options of [Handler].
+type Options struct {
+	// LiftKeys are the keys of the attributes of errors which are added to the top level of records
+	// in addition to the expanded errors.
+	LiftKeys []string
+
+	// CodeLevels are the levels of the codes.
+	// If a record has an error whose code has a higher level than the record, the level of the record is raised.
+	// Because [Handler.Enabled] is called with the original level, the records must be enabled with it.
+	// The errors in attributes added by [Handler.WithAttrs] do not raise the levels.
+	CodeLevels map[ergo.Code]slog.Level
+
+	// OmitStack omits the stack traces of the errors.
+	OmitStack bool
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// Handler is a [slog.Handler] which expands error-valued attributes in records and passes them to another handler.
+// An attribute "err" which has an error is expanded into the following attributes:
+//
+//   - err.msg: the message of the error
+//   - err.code: the package path and the key of the code obtained by [ergo.CodeOf] if the error has a code
+//   - err.attrs.*: the attributes obtained by [ergo.AttrsAll]
+//   - err.stack: the stack trace obtained by [ergo.StackTraceOf] unless [Options.OmitStack] is true
+//
+// Errors in groups are also expanded.
+type Handler struct {
+	// handler has the attributes added before the first group.
+	// The groups are not passed to it so that the lifted attributes stay at the top level.
+	handler slog.Handler
+	opts    Options
+	groups  []group
+}
+
+// group is a group added by [Handler.WithGroup] and the attributes added in it.
+type group struct {
+	name  string
+	attrs []slog.Attr
+}
+
+// NewHandler creates a [Handler] which wraps the handler.
+// If opts is nil, the default options are used.
+func NewHandler(handler slog.Handler, opts *Options) *Handler {
+	h := &Handler{handler: handler}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// Enabled implements [slog.Handler].
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements [slog.Handler].
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	e := &expansion{opts: &h.opts, level: r.Level}
+
+	var attrs []slog.Attr
+	r.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, e.expand(attr))
+		return true
+	})
+
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		g := h.groups[i]
+		attrs = []slog.Attr{{Key: g.name, Value: slog.GroupValue(append(slices.Clone(g.attrs), attrs...)...)}}
+	}
+
+	newRecord := slog.NewRecord(r.Time, e.level, r.Message, r.PC)
+	newRecord.AddAttrs(attrs...)
+	newRecord.AddAttrs(e.lifted...)
+
+	return h.handler.Handle(ctx, newRecord)
+}
+
+// WithAttrs implements [slog.Handler].
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	e := &expansion{opts: &h.opts}
+
+	expanded := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		expanded = append(expanded, e.expand(attr))
+	}
+
+	if len(h.groups) == 0 {
+		expanded = append(expanded, e.lifted...)
+		return &Handler{handler: h.handler.WithAttrs(expanded), opts: h.opts}
+	}
+
+	groups := slices.Clone(h.groups)
+	last := &groups[len(groups)-1]
+	last.attrs = append(slices.Clip(last.attrs), expanded...)
+
+	handler := h.handler
+	if len(e.lifted) > 0 {
+		handler = handler.WithAttrs(e.lifted)
+	}
+
+	return &Handler{handler: handler, opts: h.opts, groups: groups}
+}
+
+// WithGroup implements [slog.Handler].
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	groups := append(slices.Clip(h.groups), group{name: name})
+	return &Handler{handler: h.handler, opts: h.opts, groups: groups}
+}
+
+// expansion expands errors in attributes and keeps the lifted attributes and the raised level.
+type expansion struct {
+	opts   *Options
+	lifted []slog.Attr
+	level  slog.Level
+}
+
+func (e *expansion) expand(attr slog.Attr) slog.Attr {
+	switch attr.Value.Kind() {
+	case slog.KindAny, slog.KindLogValuer:
+		// errors are expanded even if they implement slog.LogValuer
+		if err, ok := attr.Value.Any().(error); ok && err != nil {
+			return slog.Attr{Key: attr.Key, Value: slog.GroupValue(e.expandError(err)...)}
+		}
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		expanded := make([]slog.Attr, len(group))
+		for i, attr := range group {
+			expanded[i] = e.expand(attr)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(expanded...)}
+	}
+
+	return attr
+}
+
+func (e *expansion) expandError(err error) []slog.Attr {
+	attrs := []slog.Attr{slog.String(KeyMessage, err.Error())}
+
+	if code := ergo.CodeOf(err); !code.IsZero() {
+		attrs = append(attrs, slog.String(KeyCode, code.PkgPath()+"."+code.Key()))
+		if level, ok := e.opts.CodeLevels[code]; ok && level > e.level {
+			e.level = level
+		}
+	}
+
+	errAttrs := slices.Collect(ergo.AttrsAll(err))
+	if len(errAttrs) > 0 {
+		attrs = append(attrs, slog.Attr{Key: KeyAttrs, Value: slog.GroupValue(errAttrs...)})
+	}
+
+	for _, attr := range errAttrs {
+		if slices.Contains(e.opts.LiftKeys, attr.Key) &&
+			!slices.ContainsFunc(e.lifted, func(lifted slog.Attr) bool { return lifted.Key == attr.Key }) {
+			e.lifted = append(e.lifted, attr)
+		}
+	}
+
+	if st := ergo.StackTraceOf(err); !e.opts.OmitStack && len(st) > 0 {
+		frames := make([]string, len(st))
+		for i, frame := range st {
+			frames[i] = fmt.Sprintf("%s %s:%d", frame.RuntimeFrame().Function, frame.File(), frame.Line())
+		}
+		attrs = append(attrs, slog.Any(KeyStack, frames))
+	}
+
+	return attrs
+}
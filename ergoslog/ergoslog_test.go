@@ -0,0 +1,177 @@
+package ergoslog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/newmo-oss/ergo/ergoslog"
+)
+
+var (
+	codeA = ergo.NewCode("A", "code A message")
+	codeB = ergo.NewCode("B", "code B message")
+)
+
+func newError() error {
+	err := ergo.New("not found", slog.String("user_id", "u1"))
+	err = ergo.WithCode(err, codeA)
+	return ergo.Wrap(err, "failed to find", slog.Int("retry", 1))
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		opts *ergoslog.Options
+		log  func(logger *slog.Logger)
+
+		want map[string]any
+	}{
+		"expand": {&ergoslog.Options{OmitStack: true}, func(logger *slog.Logger) {
+			logger.Info("msg", "err", newError())
+		}, map[string]any{
+			"level": "INFO",
+			"msg":   "msg",
+			"err": map[string]any{
+				"msg":   "failed to find: github.com/newmo-oss/ergo/ergoslog_test.A: code A message: not found",
+				"code":  "github.com/newmo-oss/ergo/ergoslog_test.A",
+				"attrs": map[string]any{"retry": 1.0, "user_id": "u1"},
+			},
+		}},
+		"no ergo": {&ergoslog.Options{OmitStack: true}, func(logger *slog.Logger) {
+			logger.Info("msg", "err", errors.New("error"))
+		}, map[string]any{
+			"level": "INFO",
+			"msg":   "msg",
+			"err":   map[string]any{"msg": "error"},
+		}},
+		"in group": {&ergoslog.Options{OmitStack: true}, func(logger *slog.Logger) {
+			logger.Info("msg", slog.Group("req", "err", ergo.New("error")))
+		}, map[string]any{
+			"level": "INFO",
+			"msg":   "msg",
+			"req":   map[string]any{"err": map[string]any{"msg": "error"}},
+		}},
+		"lift": {&ergoslog.Options{OmitStack: true, LiftKeys: []string{"user_id"}}, func(logger *slog.Logger) {
+			logger.Info("msg", "err", newError())
+		}, map[string]any{
+			"level":   "INFO",
+			"msg":     "msg",
+			"user_id": "u1",
+			"err": map[string]any{
+				"msg":   "failed to find: github.com/newmo-oss/ergo/ergoslog_test.A: code A message: not found",
+				"code":  "github.com/newmo-oss/ergo/ergoslog_test.A",
+				"attrs": map[string]any{"retry": 1.0, "user_id": "u1"},
+			},
+		}},
+		"with attrs": {&ergoslog.Options{OmitStack: true, LiftKeys: []string{"user_id"}}, func(logger *slog.Logger) {
+			logger.With("err", newError()).Info("msg")
+		}, map[string]any{
+			"level":   "INFO",
+			"msg":     "msg",
+			"user_id": "u1",
+			"err": map[string]any{
+				"msg":   "failed to find: github.com/newmo-oss/ergo/ergoslog_test.A: code A message: not found",
+				"code":  "github.com/newmo-oss/ergo/ergoslog_test.A",
+				"attrs": map[string]any{"retry": 1.0, "user_id": "u1"},
+			},
+		}},
+		"with group": {&ergoslog.Options{OmitStack: true, LiftKeys: []string{"user_id"}}, func(logger *slog.Logger) {
+			logger.WithGroup("req").With("id", "r1").WithGroup("db").Info("msg", "err", newError())
+		}, map[string]any{
+			"level":   "INFO",
+			"msg":     "msg",
+			"user_id": "u1",
+			"req": map[string]any{
+				"id": "r1",
+				"db": map[string]any{
+					"err": map[string]any{
+						"msg":   "failed to find: github.com/newmo-oss/ergo/ergoslog_test.A: code A message: not found",
+						"code":  "github.com/newmo-oss/ergo/ergoslog_test.A",
+						"attrs": map[string]any{"retry": 1.0, "user_id": "u1"},
+					},
+				},
+			},
+		}},
+		"with attrs in group": {&ergoslog.Options{OmitStack: true, LiftKeys: []string{"user_id"}}, func(logger *slog.Logger) {
+			logger.WithGroup("req").With("err", newError()).Info("msg")
+		}, map[string]any{
+			"level":   "INFO",
+			"msg":     "msg",
+			"user_id": "u1",
+			"req": map[string]any{
+				"err": map[string]any{
+					"msg":   "failed to find: github.com/newmo-oss/ergo/ergoslog_test.A: code A message: not found",
+					"code":  "github.com/newmo-oss/ergo/ergoslog_test.A",
+					"attrs": map[string]any{"retry": 1.0, "user_id": "u1"},
+				},
+			},
+		}},
+		"raise level": {&ergoslog.Options{OmitStack: true, CodeLevels: map[ergo.Code]slog.Level{codeA: slog.LevelError}}, func(logger *slog.Logger) {
+			logger.Info("msg", "err", ergo.WithCode(ergo.NewSentinel("error"), codeA))
+		}, map[string]any{
+			"level": "ERROR",
+			"msg":   "msg",
+			"err":   map[string]any{"msg": "github.com/newmo-oss/ergo/ergoslog_test.A: code A message: error", "code": "github.com/newmo-oss/ergo/ergoslog_test.A"},
+		}},
+		"not lower level": {&ergoslog.Options{OmitStack: true, CodeLevels: map[ergo.Code]slog.Level{codeB: slog.LevelInfo}}, func(logger *slog.Logger) {
+			logger.Warn("msg", "err", ergo.WithCode(ergo.NewSentinel("error"), codeB))
+		}, map[string]any{
+			"level": "WARN",
+			"msg":   "msg",
+			"err":   map[string]any{"msg": "github.com/newmo-oss/ergo/ergoslog_test.B: code B message: error", "code": "github.com/newmo-oss/ergo/ergoslog_test.B"},
+		}},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			logger := slog.New(ergoslog.NewHandler(slog.NewJSONHandler(&buf, nil), tt.opts))
+			tt.log(logger)
+
+			var got map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+			delete(got, "time")
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("log record does not match (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandlerStack(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(ergoslog.NewHandler(slog.NewJSONHandler(&buf, nil), nil))
+	logger.Info("msg", "err", newError())
+
+	var got struct {
+		Err struct {
+			Stack []string `json:"stack"`
+		} `json:"err"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(got.Err.Stack) == 0 {
+		t.Fatal("err.stack must not be empty")
+	}
+
+	if want := "github.com/newmo-oss/ergo/ergoslog_test.newError "; !strings.HasPrefix(got.Err.Stack[0], want) {
+		t.Errorf("err.stack must start with %q but got %q", want, got.Err.Stack[0])
+	}
+}
@@ -0,0 +1,180 @@
+// Package ergotest provides test helpers for errors created by [ergo].
+//
+// The assertion functions report failures via [TestingT.Errorf] with the error formatted by "%+v",
+// which includes the attributes of the error and its parents.
+//
+//	func TestFind(t *testing.T) {
+//		_, err := Find("unknown")
+//		ergotest.AssertCode(t, err, CodeNotFound)
+//		ergotest.AssertAttrs(t, err, slog.String("id", "unknown"))
+//	}
+package ergotest
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/newmo-oss/ergo"
+)
+
+// TestingT is the interface of [testing.T] which is used by the assertion functions.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// AssertCode asserts that the error has the code.
+// The code is obtained by [ergo.CodeOf], so the zero code means the error has no code.
+func AssertCode(t TestingT, err error, want ergo.Code) bool {
+	t.Helper()
+
+	if got := ergo.CodeOf(err); got != want {
+		t.Errorf("code does not match: (got, want) = (%v, %v)\nerror: %+v", got, want, err)
+		return false
+	}
+
+	return true
+}
+
+// AssertAttrs asserts that the error has the attributes.
+// The attributes are obtained by [ergo.AttrsAll] and the error may have other attributes.
+// Use [AssertAttrsExact] to assert that the error has only the attributes.
+func AssertAttrs(t TestingT, err error, want ...slog.Attr) bool {
+	t.Helper()
+
+	got := slices.Collect(ergo.AttrsAll(err))
+
+	var missing []slog.Attr
+	for _, attr := range want {
+		if !slices.ContainsFunc(got, attr.Equal) {
+			missing = append(missing, attr)
+		}
+	}
+
+	if len(missing) > 0 {
+		t.Errorf("attributes are missing: %v\ngot: %v\nerror: %+v", missing, got, err)
+		return false
+	}
+
+	return true
+}
+
+// AssertAttrsExact asserts that the error has only the attributes in the order of [ergo.AttrsAll].
+func AssertAttrsExact(t TestingT, err error, want ...slog.Attr) bool {
+	t.Helper()
+
+	got := slices.Collect(ergo.AttrsAll(err))
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("attributes do not match (-want +got):\n%s\nerror: %+v", diff, err)
+		return false
+	}
+
+	return true
+}
+
+// AssertMessageChain asserts the messages of the error and its parents from the outermost one.
+// See [MessageChain] for the messages.
+func AssertMessageChain(t TestingT, err error, want ...string) bool {
+	t.Helper()
+
+	got := MessageChain(err)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("message chain does not match (-want +got):\n%s\nerror: %+v", diff, err)
+		return false
+	}
+
+	return true
+}
+
+// MessageChain returns the messages of the error and its parents from the outermost one.
+// The message of each error is its own part of the result of the Error method,
+// which does not include the message of the parent.
+// The errors which have only a code or an empty message are skipped.
+//
+//	err := ergo.Wrap(ergo.New("not found"), "failed to find")
+//	MessageChain(err) // ["failed to find", "not found"]
+func MessageChain(err error) []string {
+	var msgs []string
+	for err != nil {
+		parent := errors.Unwrap(err)
+		if parent == nil {
+			msgs = append(msgs, err.Error())
+			break
+		}
+
+		msg, parentMsg := err.Error(), parent.Error()
+		switch {
+		case msg == parentMsg:
+			// empty message
+		case ergo.CodeOf(err) != ergo.CodeOf(parent):
+			// the error attaches a code
+		default:
+			msgs = append(msgs, strings.TrimSuffix(strings.TrimSuffix(msg, parentMsg), ": "))
+		}
+
+		err = parent
+	}
+	return msgs
+}
+
+// AssertStackContains asserts that the stack trace of the error has a frame of the function.
+// The frame filters set by [ergo.SetFrameFilter] are not applied.
+// The function name can be a fully qualified name such as "example.com/a.F.func1",
+// a name with the package name such as "a.F.func1" or a name without the package such as "F.func1".
+func AssertStackContains(t TestingT, err error, funcName string) bool {
+	t.Helper()
+
+	var funcNames []string
+	for _, frame := range ergo.RawStackTraceOf(err) {
+		if frame.RuntimeFrame().Function == funcName ||
+			frame.PkgName()+"."+frame.FuncName() == funcName ||
+			frame.FuncName() == funcName {
+			return true
+		}
+		funcNames = append(funcNames, frame.RuntimeFrame().Function)
+	}
+
+	t.Errorf("stack trace does not contain %s: %q\nerror: %+v", funcName, funcNames, err)
+	return false
+}
+
+// Structure is the structure of an error which is compared by [CompareErrors].
+type Structure struct {
+	// Messages is the result of [MessageChain].
+	Messages []string
+	// Attrs is the result of [ergo.AttrsAll].
+	Attrs []slog.Attr
+	// Code is the string representation of the result of [ergo.CodeOf].
+	Code string
+}
+
+// StructureOf returns the structure of the error.
+func StructureOf(err error) Structure {
+	var code string
+	if c := ergo.CodeOf(err); !c.IsZero() {
+		code = c.String()
+	}
+
+	return Structure{
+		Messages: MessageChain(err),
+		Attrs:    slices.Collect(ergo.AttrsAll(err)),
+		Code:     code,
+	}
+}
+
+// CompareErrors returns a [cmp.Option] which compares non-nil errors by their structures.
+// The errors are equal if their messages, attributes and codes are equal
+// even if their stack traces are different.
+//
+//	if diff := cmp.Diff(want, got, ergotest.CompareErrors()); diff != "" {
+//		t.Error(diff)
+//	}
+func CompareErrors() cmp.Option {
+	return cmp.FilterValues(func(x, y error) bool {
+		return x != nil && y != nil
+	}, cmp.Transformer("ergotest.StructureOf", StructureOf))
+}
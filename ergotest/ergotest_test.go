@@ -0,0 +1,211 @@
+package ergotest_test
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/newmo-oss/ergo/ergotest"
+)
+
+var (
+	codeA = ergo.NewCode("A", "code A message")
+	codeB = ergo.NewCode("B", "code B message")
+)
+
+// fakeT records the failures of assertions.
+type fakeT struct {
+	failures []string
+}
+
+func (*fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func newError() error {
+	err := ergo.New("not found", slog.String("id", "id1"))
+	err = ergo.WithCode(err, codeA)
+	return ergo.Wrap(err, "failed to find", slog.Int("retry", 1))
+}
+
+func TestAssertCode(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		err  error
+		code ergo.Code
+		want bool
+	}{
+		"match":     {newError(), codeA, true},
+		"not match": {newError(), codeB, false},
+		"no code":   {ergo.New("error"), ergo.Code{}, true},
+		"nil":       {nil, codeA, false},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var ft fakeT
+			got := ergotest.AssertCode(&ft, tt.err, tt.code)
+			assertResult(t, &ft, got, tt.want)
+		})
+	}
+}
+
+func TestAssertAttrs(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		err   error
+		attrs []slog.Attr
+		exact bool
+		want  bool
+	}{
+		"subset":                {newError(), []slog.Attr{slog.String("id", "id1")}, false, true},
+		"all":                   {newError(), []slog.Attr{slog.Int("retry", 1), slog.String("id", "id1")}, false, true},
+		"none":                  {newError(), nil, false, true},
+		"different value":       {newError(), []slog.Attr{slog.String("id", "id2")}, false, false},
+		"missing":               {newError(), []slog.Attr{slog.String("name", "foo")}, false, false},
+		"exact":                 {newError(), []slog.Attr{slog.Int("retry", 1), slog.String("id", "id1")}, true, true},
+		"exact with subset":     {newError(), []slog.Attr{slog.String("id", "id1")}, true, false},
+		"exact different order": {newError(), []slog.Attr{slog.String("id", "id1"), slog.Int("retry", 1)}, true, false},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				ft  fakeT
+				got bool
+			)
+			if tt.exact {
+				got = ergotest.AssertAttrsExact(&ft, tt.err, tt.attrs...)
+			} else {
+				got = ergotest.AssertAttrs(&ft, tt.err, tt.attrs...)
+			}
+			assertResult(t, &ft, got, tt.want)
+		})
+	}
+}
+
+func TestAssertMessageChain(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		err  error
+		msgs []string
+		want bool
+	}{
+		"match":         {newError(), []string{"failed to find", "not found"}, true},
+		"not match":     {newError(), []string{"failed to find"}, false},
+		"empty message": {ergo.Wrap(ergo.New("not found"), ""), []string{"not found"}, true},
+		"sentinel":      {ergo.Wrap(ergo.NewSentinel("sentinel"), "failed"), []string{"failed", "sentinel"}, true},
+		"fmt.Errorf":    {fmt.Errorf("failed: %w", errors.New("not found")), []string{"failed", "not found"}, true},
+		"nil":           {nil, nil, true},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var ft fakeT
+			got := ergotest.AssertMessageChain(&ft, tt.err, tt.msgs...)
+			assertResult(t, &ft, got, tt.want)
+		})
+	}
+}
+
+func TestAssertStackContains(t *testing.T) {
+	t.Parallel()
+
+	err := newError()
+
+	cases := map[string]struct {
+		funcName string
+		want     bool
+	}{
+		"full name":      {"github.com/newmo-oss/ergo/ergotest_test.newError", true},
+		"package name":   {"ergotest_test.newError", true},
+		"function name":  {"newError", true},
+		"other function": {"TestAssertCode", false},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var ft fakeT
+			got := ergotest.AssertStackContains(&ft, err, tt.funcName)
+			assertResult(t, &ft, got, tt.want)
+		})
+	}
+}
+
+func TestCompareErrors(t *testing.T) {
+	t.Parallel()
+
+	type result struct {
+		Err error
+	}
+
+	cases := map[string]struct {
+		x, y error
+		want bool
+	}{
+		"same structure":    {newError(), newError(), true},
+		"different message": {ergo.New("error1"), ergo.New("error2"), false},
+		"different attrs":   {ergo.New("error", slog.Int("n", 1)), ergo.New("error", slog.Int("n", 2)), false},
+		"different code":    {ergo.WithCode(ergo.New("error"), codeA), ergo.WithCode(ergo.New("error"), codeB), false},
+		"both nil":          {nil, nil, true},
+		"one nil":           {ergo.New("error"), nil, false},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := cmp.Equal(result{tt.x}, result{tt.y}, ergotest.CompareErrors())
+			if got != tt.want {
+				t.Errorf("cmp.Equal does not match: (got, want) = (%v, %v)\ndiff:\n%s", got, tt.want,
+					cmp.Diff(result{tt.x}, result{tt.y}, ergotest.CompareErrors()))
+			}
+		})
+	}
+}
+
+func TestFailureMessage(t *testing.T) {
+	t.Parallel()
+
+	var ft fakeT
+	ergotest.AssertCode(&ft, newError(), codeB)
+
+	if len(ft.failures) != 1 {
+		t.Fatalf("the number of failures does not match: (got, want) = (%d, %d)", len(ft.failures), 1)
+	}
+
+	// the error is formatted with %+v
+	if want := "failed to find: retry=1"; !strings.Contains(ft.failures[0], want) {
+		t.Errorf("the failure message does not contain %q: %s", want, ft.failures[0])
+	}
+}
+
+func assertResult(t *testing.T, ft *fakeT, got, want bool) {
+	t.Helper()
+
+	if got != want {
+		t.Errorf("result does not match: (got, want) = (%v, %v): %q", got, want, ft.failures)
+	}
+
+	if got == (len(ft.failures) > 0) {
+		t.Errorf("the result and failures are inconsistent: %v, %q", got, ft.failures)
+	}
+}
@@ -0,0 +1,125 @@
+package ergotest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/newmo-oss/ergo"
+)
+
+// UpdateGolden reports whether [AssertGolden] rewrites the golden files instead of comparing with them.
+// It is initialized by the environment variable ERGOTEST_UPDATE_GOLDEN.
+// A test package which has its own -update flag can set it in TestMain:
+//
+//	var update = flag.Bool("update", false, "update the golden files")
+//
+//	func TestMain(m *testing.M) {
+//		flag.Parse()
+//		ergotest.UpdateGolden = *update
+//		os.Exit(m.Run())
+//	}
+var UpdateGolden = os.Getenv("ERGOTEST_UPDATE_GOLDEN") != ""
+
+// AssertGolden asserts that the snapshot of the error matches the golden file "testdata/<name>.golden".
+// See [AssertGoldenFile] for the details.
+//
+//	ERGOTEST_UPDATE_GOLDEN=1 go test -run TestFind
+func AssertGolden(t TestingT, err error, name string) bool {
+	t.Helper()
+	return AssertGoldenFile(t, err, filepath.Join("testdata", name+".golden"))
+}
+
+// AssertGoldenFile asserts that the snapshot of the error matches the golden file of the path.
+// The snapshot is created by [Snapshot].
+// If [UpdateGolden] is true, the golden file is rewritten with the snapshot.
+func AssertGoldenFile(t TestingT, err error, path string) bool {
+	t.Helper()
+
+	got := Snapshot(err)
+
+	if UpdateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("failed to create the directory of the golden file %s: %v", path, err)
+			return false
+		}
+
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Errorf("failed to write the golden file %s: %v", path, err)
+			return false
+		}
+
+		return true
+	}
+
+	want, rerr := os.ReadFile(path)
+	if rerr != nil {
+		t.Errorf("failed to read the golden file %s (set ERGOTEST_UPDATE_GOLDEN=1 to create it): %v", path, rerr)
+		return false
+	}
+
+	if diff := cmp.Diff(strings.Split(string(want), "\n"), strings.Split(got, "\n")); diff != "" {
+		t.Errorf("snapshot does not match the golden file %s (-want +got):\n%s\nerror: %+v", path, diff, err)
+		return false
+	}
+
+	return true
+}
+
+// Snapshot returns the stable text form of the error.
+// It has the messages of [MessageChain], the codes attached in the chain of the error,
+// the attributes of [ergo.AttrsAll] and the function names of the stack trace.
+// The stack trace does not have line numbers and frames of the runtime and testing packages,
+// thus the snapshot does not change when unrelated code moves or the Go version is updated.
+//
+//	messages:
+//	  failed to find
+//	  not found
+//	codes:
+//	  example.com/repo.NotFound: not found
+//	attrs:
+//	  id=id1
+//	stack:
+//	  example.com/repo.Find
+//	  example.com/repo.TestFind
+func Snapshot(err error) string {
+	var sb strings.Builder
+
+	section := func(name string, lines []string) {
+		sb.WriteString(name + ":\n")
+		for _, line := range lines {
+			sb.WriteString("  " + line + "\n")
+		}
+	}
+
+	section("messages", MessageChain(err))
+
+	var codes []string
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if code := ergo.CodeOf(e); !code.IsZero() && code != ergo.CodeOf(errors.Unwrap(e)) {
+			codes = append(codes, code.String())
+		}
+	}
+	section("codes", codes)
+
+	var attrs []string
+	for attr := range ergo.AttrsAll(err) {
+		attrs = append(attrs, attr.String())
+	}
+	section("attrs", attrs)
+
+	var frames []string
+	for _, frame := range ergo.StackTraceOf(err) {
+		switch frame.PkgPath() {
+		case "runtime", "testing":
+			continue
+		}
+		frames = append(frames, frame.RuntimeFrame().Function)
+	}
+	section("stack", frames)
+
+	return sb.String()
+}
@@ -0,0 +1,52 @@
+package ergotest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/newmo-oss/ergo/ergotest"
+)
+
+func TestAssertGolden(t *testing.T) {
+	t.Parallel()
+
+	var ft fakeT
+	got := ergotest.AssertGolden(&ft, newError(), "error")
+	assertResult(t, &ft, got, true)
+}
+
+func TestAssertGoldenFile(t *testing.T) {
+	t.Parallel()
+
+	if ergotest.UpdateGolden {
+		t.Skip("the golden files are rewritten")
+	}
+
+	dir := t.TempDir()
+	golden := filepath.Join(dir, "error.golden")
+	if err := os.WriteFile(golden, []byte(ergotest.Snapshot(newError())), 0o644); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cases := map[string]struct {
+		err  error
+		path string
+		want bool
+	}{
+		"match":     {newError(), golden, true},
+		"not match": {ergo.Wrap(newError(), "other"), golden, false},
+		"no golden": {newError(), filepath.Join(dir, "notexist.golden"), false},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var ft fakeT
+			got := ergotest.AssertGoldenFile(&ft, tt.err, tt.path)
+			assertResult(t, &ft, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package ergo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/newmo-oss/go-caller"
+)
+
+const defaultFingerprintFrames = 5
+
+type fingerprintConfig struct {
+	codes    bool
+	messages bool
+	frames   int
+	module   string
+	lines    bool
+}
+
+// FingerprintOption is an option of [Fingerprint].
+type FingerprintOption func(*fingerprintConfig)
+
+// FingerprintCodes sets whether the codes attached by [WithCode] participate in the fingerprint.
+// The default is true.
+func FingerprintCodes(enabled bool) FingerprintOption {
+	return func(config *fingerprintConfig) {
+		config.codes = enabled
+	}
+}
+
+// FingerprintMessages sets whether the messages of the errors participate in the fingerprint.
+// The default is true.
+func FingerprintMessages(enabled bool) FingerprintOption {
+	return func(config *fingerprintConfig) {
+		config.messages = enabled
+	}
+}
+
+// FingerprintFrames sets the number of the stack frames which participate in the fingerprint.
+// If n is 0 or less, the stack frames do not participate in the fingerprint.
+// The default is 5.
+func FingerprintFrames(n int) FingerprintOption {
+	return func(config *fingerprintConfig) {
+		config.frames = max(n, 0)
+	}
+}
+
+// FingerprintModule sets the prefix of the packages whose stack frames participate in the fingerprint
+// such as a module path.
+// By default, the frames of the packages out of the standard library participate as same as [DropStdlib].
+func FingerprintModule(prefix string) FingerprintOption {
+	return func(config *fingerprintConfig) {
+		config.module = prefix
+	}
+}
+
+// FingerprintLines sets whether the line numbers of the stack frames participate in the fingerprint.
+// The default is false, thus the fingerprint does not change when unrelated code moves.
+func FingerprintLines(enabled bool) FingerprintOption {
+	return func(config *fingerprintConfig) {
+		config.lines = enabled
+	}
+}
+
+// Fingerprint returns a fingerprint of the error to group identical failures such as in error trackers.
+// The fingerprint does not depend on the values of attributes,
+// thus errors created at the same place with different attributes have the same fingerprint.
+// If err is nil, Fingerprint returns an empty string.
+//
+// The fingerprint is the first 16 hex digits of the SHA-256 hash of the following lines joined by "\n",
+// which are listed from the outermost error:
+//
+//	code:<package path of the code>.<key of the code>
+//	msg:<message>
+//	frame:<fully qualified function name>[:<line number>]
+//
+// The message of an error created by [New] or [Wrap] is the msg argument and it is omitted if it is empty.
+// The message of another error is its own part of the result of the Error method.
+// The errors joined by [errors.Join] are walked in depth-first order.
+// The frames are the first ones of the stack trace returned by [RawStackTraceOf]
+// which are not affected by [SetFrameFilter].
+// The algorithm does not change in the same major version.
+func Fingerprint(err error, opts ...FingerprintOption) string {
+	if err == nil {
+		return ""
+	}
+
+	config := &fingerprintConfig{
+		codes:    true,
+		messages: true,
+		frames:   defaultFingerprintFrames,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	lines := appendFingerprintLines(nil, err, config)
+
+	if config.frames > 0 {
+		var n int
+		for _, frame := range RawStackTraceOf(err) {
+			if n >= config.frames {
+				break
+			}
+
+			if !fingerprintFrame(frame, config.module) {
+				continue
+			}
+			n++
+
+			line := "frame:" + frame.RuntimeFrame().Function
+			if config.lines {
+				line += ":" + strconv.Itoa(frame.Line())
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// appendFingerprintLines appends the lines of the code and the message of the error and its parents in depth-first order.
+func appendFingerprintLines(lines []string, err error, config *fingerprintConfig) []string {
+	parents := parentsOf(err)
+
+	switch e := err.(type) {
+	case *codedError:
+		if config.codes {
+			lines = append(lines, "code:"+e.code.PkgPath()+"."+e.code.Key())
+		}
+	case *defaultError:
+		if config.messages && e.msg != "" {
+			lines = append(lines, "msg:"+e.msg)
+		}
+	default:
+		if config.messages {
+			lines = append(lines, "msg:"+ownMessage(e, parents))
+		}
+	}
+
+	for _, parent := range parents {
+		lines = appendFingerprintLines(lines, parent, config)
+	}
+
+	return lines
+}
+
+// parentsOf returns the errors which are wrapped by the error except nil ones.
+func parentsOf(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return slices.DeleteFunc(slices.Clone(joined.Unwrap()), func(err error) bool {
+			return err == nil
+		})
+	}
+
+	if parent := errors.Unwrap(err); parent != nil {
+		return []error{parent}
+	}
+
+	return nil
+}
+
+// ownMessage returns the message of the error without the messages of its parents.
+// The message of an error which joins errors such as [errors.Join] is empty
+// if it is only the messages of the joined errors.
+func ownMessage(err error, parents []error) string {
+	msg := err.Error()
+	switch len(parents) {
+	case 0:
+		return msg
+	case 1:
+		return strings.TrimSuffix(strings.TrimSuffix(msg, parents[0].Error()), ": ")
+	}
+
+	msgs := make([]string, len(parents))
+	for i, parent := range parents {
+		msgs[i] = parent.Error()
+	}
+
+	if msg == strings.Join(msgs, "\n") {
+		// errors.Join
+		return ""
+	}
+
+	return msg
+}
+
+func fingerprintFrame(frame caller.Frame, module string) bool {
+	if module != "" {
+		return hasPackagePrefix(frame.PkgPath(), module)
+	}
+	return DropStdlib(frame)
+}
@@ -0,0 +1,83 @@
+package ergo_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/newmo-oss/ergo"
+)
+
+var codeB = ergo.NewCode("B", "code B message")
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	findError := func(id string) error {
+		return ergo.Wrap(ergo.New("not found", slog.String("id", id)), "failed to find")
+	}
+	otherError := func(id string) error {
+		return ergo.Wrap(ergo.New("not found", slog.String("id", id)), "failed to find")
+	}
+	twoErrors := func() (error, error) {
+		err1 := ergo.New("error")
+		err2 := ergo.New("error")
+		return err1, err2
+	}
+	err1, err2 := twoErrors()
+
+	cases := map[string]struct {
+		x, y error
+		opts []ergo.FingerprintOption
+		want bool // whether the fingerprints are equal
+	}{
+		"different attrs":             {findError("id1"), findError("id2"), nil, true},
+		"different messages":          {ergo.Wrap(findError("id1"), "a"), ergo.Wrap(findError("id1"), "b"), nil, false},
+		"ignore messages":             {ergo.Wrap(findError("id1"), "a"), ergo.Wrap(findError("id1"), "b"), options(ergo.FingerprintMessages(false)), true},
+		"empty message":               {findError("id1"), ergo.Wrap(findError("id1"), ""), nil, true},
+		"different codes":             {ergo.WithCode(findError("id1"), codeA), ergo.WithCode(findError("id1"), codeB), nil, false},
+		"ignore codes":                {ergo.WithCode(findError("id1"), codeA), ergo.WithCode(findError("id1"), codeB), options(ergo.FingerprintCodes(false)), true},
+		"different functions":         {findError("id1"), otherError("id1"), nil, false},
+		"ignore frames":               {findError("id1"), otherError("id1"), options(ergo.FingerprintFrames(0)), true},
+		"frames in other module":      {findError("id1"), otherError("id1"), options(ergo.FingerprintModule("example.com")), true},
+		"frames in module":            {findError("id1"), otherError("id1"), options(ergo.FingerprintModule("github.com/newmo-oss/ergo_test")), false},
+		"different lines":             {err1, err2, nil, true},
+		"different lines with lines":  {err1, err2, options(ergo.FingerprintLines(true)), false},
+		"non ergo error":              {fmt.Errorf("a: %w", errors.New("b")), fmt.Errorf("a: %w", errors.New("b")), nil, true},
+		"different non ergo messages": {fmt.Errorf("a: %w", errors.New("b")), fmt.Errorf("a: %w", errors.New("c")), nil, false},
+		"different non ergo wrappers": {fmt.Errorf("a: %w", errors.New("b")), fmt.Errorf("c: %w", errors.New("b")), nil, false},
+		"joined":                      {errors.Join(findError("id1"), errors.New("a")), errors.Join(findError("id2"), errors.New("a")), nil, true},
+		"different joined codes":      {errors.Join(ergo.WithCode(findError("id1"), codeA)), errors.Join(ergo.WithCode(findError("id1"), codeB)), options(ergo.FingerprintMessages(false)), false},
+		"ignore joined messages":      {errors.Join(ergo.Wrap(findError("id1"), "a")), errors.Join(ergo.Wrap(findError("id1"), "b")), options(ergo.FingerprintMessages(false)), true},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			x, y := ergo.Fingerprint(tt.x, tt.opts...), ergo.Fingerprint(tt.y, tt.opts...)
+			if got := x == y; got != tt.want {
+				t.Errorf("fingerprints are equal: (got, want) = (%v, %v): %s, %s", got, tt.want, x, y)
+			}
+		})
+	}
+}
+
+func TestFingerprintAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	err := ergo.Wrap(ergo.WithCode(ergo.New("not found", slog.String("id", "id1")), codeA), "failed to find")
+	sum := sha256.Sum256([]byte("msg:failed to find\ncode:github.com/newmo-oss/ergo_test.A\nmsg:not found"))
+	want := hex.EncodeToString(sum[:8])
+
+	if got := ergo.Fingerprint(err, ergo.FingerprintFrames(0)); got != want {
+		t.Errorf("Fingerprint does not match: (got, want) = (%q, %q)", got, want)
+	}
+
+	if got := ergo.Fingerprint(nil); got != "" {
+		t.Errorf("Fingerprint(nil) must be empty but got %q", got)
+	}
+}
@@ -0,0 +1,126 @@
+package ergo
+
+import (
+	"errors"
+	"iter"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// catalog is the registered messages.
+// The maps of each language are replaced by copies on registering, thus they are read without the lock.
+var catalog = struct {
+	mu       sync.RWMutex
+	messages map[string]map[Code]*template.Template
+}{
+	messages: make(map[string]map[Code]*template.Template),
+}
+
+// RegisterMessages registers the user-facing messages of the codes in the language.
+// The messages are templates of [text/template] which are executed with the attributes of the error
+// obtained by [AttrsAll] such as "user {{.user_id}} is not found".
+// The attributes in a group can be referred as "{{.group.key}}".
+// The message of the zero [Code] is the default message of the language.
+// The messages are separated from the internal messages of the codes which are returned by [Code.Message].
+// Registering a message of the same code and language again overwrites the previous one.
+func RegisterMessages(lang string, messages map[Code]string) error {
+	tmpls := make(map[Code]*template.Template, len(messages))
+	for code, msg := range messages {
+		tmpl, err := template.New(code.String()).Option("missingkey=error").Parse(msg)
+		if err != nil {
+			return Wrap(err, "failed to parse the message", slog.String("lang", lang), slog.String("code", code.String()))
+		}
+		tmpls[code] = tmpl
+	}
+
+	catalog.mu.Lock()
+	defer catalog.mu.Unlock()
+
+	registered := maps.Clone(catalog.messages[lang])
+	if registered == nil {
+		registered = make(map[Code]*template.Template, len(tmpls))
+	}
+	maps.Copy(registered, tmpls)
+	catalog.messages[lang] = registered
+
+	return nil
+}
+
+// UserMessage returns the user-facing message of the error in the language which is registered by [RegisterMessages].
+// The message is the one of the outermost code in the chain of the error which has a message in the language.
+// If no code has a message or executing the template fails, UserMessage returns the default message of the language,
+// which is the message of the zero [Code].
+// If the language has no default message, UserMessage returns an empty string.
+// UserMessage never returns the internal messages such as the result of the Error method.
+func UserMessage(err error, lang string) string {
+	catalog.mu.RLock()
+	messages := catalog.messages[lang]
+	catalog.mu.RUnlock()
+
+	if err != nil {
+		outer := attrsData(AttrsAll(err))
+		for _, coded := range codedErrorsOf(err) {
+			tmpl := messages[coded.code]
+			if tmpl == nil {
+				continue
+			}
+
+			// the attributes of the outer errors take precedence over the ones under the code
+			data := attrsData(AttrsAll(coded))
+			maps.Copy(data, outer)
+
+			var sb strings.Builder
+			if err := tmpl.Execute(&sb, data); err == nil {
+				return sb.String()
+			}
+		}
+	}
+
+	if tmpl := messages[Code{}]; tmpl != nil {
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, map[string]any{}); err == nil {
+			return sb.String()
+		}
+	}
+
+	return ""
+}
+
+// codedErrorsOf returns the errors which attach codes in the chain of the error from the outermost one.
+// The errors joined by [errors.Join] are walked in depth-first order.
+func codedErrorsOf(err error) []*codedError {
+	var codedErrors []*codedError
+	for err != nil {
+		if codedError, ok := err.(*codedError); ok {
+			codedErrors = append(codedErrors, codedError)
+		}
+
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, err := range joined.Unwrap() {
+				codedErrors = append(codedErrors, codedErrorsOf(err)...)
+			}
+			break
+		}
+
+		err = errors.Unwrap(err)
+	}
+	return codedErrors
+}
+
+// attrsData converts the attributes into the data of templates.
+func attrsData(attrs iter.Seq[slog.Attr]) map[string]any {
+	data := make(map[string]any)
+	for attr := range attrs {
+		value := attr.Value.Resolve()
+		if value.Kind() == slog.KindGroup {
+			data[attr.Key] = attrsData(slices.Values(value.Group()))
+			continue
+		}
+		data[attr.Key] = value.Any()
+	}
+	return data
+}
@@ -0,0 +1,114 @@
+package ergo_test
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/newmo-oss/ergo"
+)
+
+func TestUserMessage(t *testing.T) {
+	t.Parallel()
+
+	var (
+		codeNotFound  = ergo.NewCode("NotFound", "not found")
+		codeForbidden = ergo.NewCode("Forbidden", "forbidden")
+		codeNoMessage = ergo.NewCode("NoMessage", "no message")
+	)
+
+	// the languages are only for this test because the messages are shared
+	if err := ergo.RegisterMessages("test-en", map[ergo.Code]string{
+		{}:            "something went wrong",
+		codeNotFound:  "user {{.user_id}} is not found",
+		codeForbidden: "{{.req.method}} is forbidden",
+	}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := ergo.RegisterMessages("test-ja", map[ergo.Code]string{
+		codeNotFound: "ユーザ{{.user_id}}が見つかりません",
+	}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	notFound := ergo.WithCode(ergo.New("no rows", slog.Int("user_id", 100)), codeNotFound)
+
+	cases := map[string]struct {
+		err  error
+		lang string
+
+		want string
+	}{
+		"en":                     {notFound, "test-en", "user 100 is not found"},
+		"ja":                     {notFound, "test-ja", "ユーザ100が見つかりません"},
+		"wrapped":                {ergo.Wrap(notFound, "failed to find"), "test-en", "user 100 is not found"},
+		"outermost":              {ergo.WithCode(notFound, codeForbidden), "test-ja", "ユーザ100が見つかりません"},
+		"outermost with message": {ergo.WithCode(ergo.Wrap(notFound, "", slog.Group("req", slog.String("method", "GET"))), codeForbidden), "test-en", "GET is forbidden"},
+		"no message":             {ergo.WithCode(notFound, codeNoMessage), "test-en", "user 100 is not found"},
+		"missing attribute":      {ergo.WithCode(ergo.New("error"), codeNotFound), "test-en", "something went wrong"},
+		"default":                {ergo.WithCode(ergo.New("error"), codeNoMessage), "test-en", "something went wrong"},
+		"no code":                {ergo.New("error"), "test-en", "something went wrong"},
+		"no default":             {ergo.New("error"), "test-ja", ""},
+		"unknown language":       {notFound, "test-unknown", ""},
+		"nil":                    {nil, "test-en", "something went wrong"},
+		"joined":                 {errors.Join(ergo.New("error"), notFound), "test-en", "user 100 is not found"},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ergo.UserMessage(tt.err, tt.lang); got != tt.want {
+				t.Errorf("UserMessage does not match: (got, want) = (%q, %q)", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterMessages(t *testing.T) {
+	t.Parallel()
+
+	code := ergo.NewCode("Invalid", "invalid")
+
+	if err := ergo.RegisterMessages("test-invalid", map[ergo.Code]string{code: "{{.user_id"}); err == nil {
+		t.Error("expected error but got nil")
+	}
+
+	if got := ergo.UserMessage(ergo.WithCode(ergo.New("error"), code), "test-invalid"); got != "" {
+		t.Errorf("UserMessage must be empty but got %q", got)
+	}
+}
+
+func TestRegisterMessagesConcurrently(t *testing.T) {
+	t.Parallel()
+
+	codes := make([]ergo.Code, 10)
+	for i := range codes {
+		codes[i] = ergo.NewCode(fmt.Sprintf("Concurrent%d", i), "concurrent")
+	}
+
+	var wg sync.WaitGroup
+	for i, code := range codes {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := ergo.RegisterMessages("test-concurrent", map[ergo.Code]string{code: fmt.Sprintf("message %d", i)}); err != nil {
+				t.Error("unexpected error:", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			_ = ergo.UserMessage(ergo.WithCode(ergo.New("error"), code), "test-concurrent")
+		}()
+	}
+	wg.Wait()
+
+	for i, code := range codes {
+		if got, want := ergo.UserMessage(ergo.WithCode(ergo.New("error"), code), "test-concurrent"), fmt.Sprintf("message %d", i); got != want {
+			t.Errorf("UserMessage does not match: (got, want) = (%q, %q)", got, want)
+		}
+	}
+}
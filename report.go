@@ -0,0 +1,173 @@
+package ergo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// ANSI escape sequences which are used by [Report].
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiFaint  = "\x1b[2m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+type reportConfig struct {
+	stack bool
+	color bool
+}
+
+// ReportOption is an option of [Report].
+type ReportOption func(*reportConfig)
+
+// ReportStack sets whether [Report] prints the stack trace of the error obtained by [StackTraceOf].
+// The default is false.
+func ReportStack(enabled bool) ReportOption {
+	return func(config *reportConfig) {
+		config.stack = enabled
+	}
+}
+
+// ReportColor sets whether [Report] colours the report with ANSI escape sequences.
+// The default is false.
+func ReportColor(enabled bool) ReportOption {
+	return func(config *reportConfig) {
+		config.color = enabled
+	}
+}
+
+// Report prints the chain of the error as an indented tree for humans such as users of CLIs.
+// Each error in the chain is printed in a line with its message and the codes attached by [WithCode],
+// followed by its attributes as aligned "key: value" lines.
+// The errors joined by [errors.Join] are printed as subtrees.
+// If err is nil, Report prints nothing.
+//
+//	failed to find
+//	│  retry: 1
+//	└─ not found [example.com/repo.NotFound]
+//	      id     : u1
+//	      user_id: 100
+func Report(w io.Writer, err error, opts ...ReportOption) error {
+	if err == nil {
+		return nil
+	}
+
+	p := &reportPrinter{}
+	for _, opt := range opts {
+		opt(&p.config)
+	}
+
+	p.node(err, "", "", "")
+
+	if st := StackTraceOf(err); p.config.stack && len(st) > 0 {
+		p.sb.WriteString("\n" + p.colored(ansiBold, "stack:") + "\n")
+		for _, frame := range st {
+			fmt.Fprintf(&p.sb, "  %s\n      %s\n", frame.RuntimeFrame().Function, p.colored(ansiFaint, fmt.Sprintf("%s:%d", frame.File(), frame.Line())))
+		}
+	}
+
+	if _, err := io.WriteString(w, p.sb.String()); err != nil {
+		return Wrap(err, "failed to write the report")
+	}
+
+	return nil
+}
+
+type reportPrinter struct {
+	config reportConfig
+	sb     strings.Builder
+}
+
+// node prints the error and its parents.
+// The line of the error starts with prefix and connector,
+// and the lines of its attributes and parents start with childPrefix.
+func (p *reportPrinter) node(err error, prefix, connector, childPrefix string) {
+	msg, codes, attrs, children := reportLayer(err)
+
+	line := p.colored(ansiBold, msg)
+	switch {
+	case msg == "" && len(children) > 1:
+		line = p.colored(ansiFaint, "(joined errors)")
+	case msg == "":
+		line = p.colored(ansiFaint, "(no message)")
+	}
+	for _, code := range codes {
+		line += " " + p.colored(ansiYellow, "["+code.PkgPath()+"."+code.Key()+"]")
+	}
+	p.sb.WriteString(prefix + connector + line + "\n")
+
+	attrPrefix := childPrefix + "   "
+	if len(children) > 0 {
+		attrPrefix = childPrefix + "│  "
+	}
+
+	var width int
+	for _, attr := range attrs {
+		width = max(width, len(attr.Key))
+	}
+	for _, attr := range attrs {
+		key := fmt.Sprintf("%-*s", width, attr.Key)
+		p.sb.WriteString(attrPrefix + p.colored(ansiCyan, key) + ": " + attr.Value.Resolve().String() + "\n")
+	}
+
+	for i, child := range children {
+		if i == len(children)-1 {
+			p.node(child, childPrefix, "└─ ", childPrefix+"   ")
+		} else {
+			p.node(child, childPrefix, "├─ ", childPrefix+"│  ")
+		}
+	}
+}
+
+func (p *reportPrinter) colored(color, s string) string {
+	if !p.config.color || s == "" {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+// reportLayer returns the message, the codes and the attributes of the error and its parents.
+// The errors which only attach codes or have no message and no attributes are merged into their parents.
+func reportLayer(err error) (msg string, codes []Code, attrs []slog.Attr, parents []error) {
+	for {
+		switch e := err.(type) {
+		case *codedError:
+			codes = append(codes, e.code)
+			err = e.parent
+			continue
+		case *defaultError:
+			if e.msg == "" && len(e.attrs) == 0 && e.parent != nil {
+				err = e.parent
+				continue
+			}
+
+			if e.parent != nil {
+				parents = []error{e.parent}
+			}
+			return e.msg, codes, e.attrs, parents
+		case interface{ Unwrap() []error }:
+			parents = parentsOf(err)
+			return ownMessage(err, parents), codes, nil, parents
+		}
+
+		msg = err.Error()
+		parent := errors.Unwrap(err)
+		if parent == nil {
+			return msg, codes, nil, nil
+		}
+
+		if own, ok := strings.CutSuffix(msg, parent.Error()); ok {
+			msg = strings.TrimSuffix(own, ": ")
+		}
+		if msg == "" {
+			err = parent
+			continue
+		}
+		return msg, codes, nil, []error{parent}
+	}
+}
@@ -0,0 +1,38 @@
+package report
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+var _ Reporter = (*MemoryReporter)(nil)
+
+// MemoryReporter is a [Reporter] which keeps the reported events in memory for tests.
+// The zero value is ready to use.
+type MemoryReporter struct {
+	mu     sync.Mutex
+	events []*Event
+}
+
+// Report implements [Reporter].
+func (r *MemoryReporter) Report(_ context.Context, event *Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+// Events returns the reported events in the reported order.
+func (r *MemoryReporter) Events() []*Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.events)
+}
+
+// Reset removes the reported events.
+func (r *MemoryReporter) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
@@ -0,0 +1,222 @@
+// Package report provides a model of error events for error trackers such as Sentry and OpenTelemetry.
+// The adapters of the trackers can convert an [Event] built by [FromError] instead of walking the chain of an error.
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/newmo-oss/ergo"
+)
+
+// ErgoType is the type of the exceptions of the errors created by [ergo.New], [ergo.Wrap] and [ergo.WithCode].
+// Their Go types are unexported and may be changed, so this stable name is used instead of them.
+const ErgoType = "ergo.Error"
+
+// ergoPkgPath is the import path of the ergo package.
+var ergoPkgPath = reflect.TypeFor[ergo.Code]().PkgPath()
+
+// Reporter reports error events to an error tracker.
+type Reporter interface {
+	Report(ctx context.Context, event *Event) error
+}
+
+// Event is an error event which is reported by a [Reporter].
+type Event struct {
+	// Message is the result of the Error method of the error.
+	Message string
+	// Code is the result of [ergo.CodeOf].
+	Code ergo.Code
+	// Attrs is the result of [ergo.AttrsAll].
+	Attrs []slog.Attr
+	// Fingerprint is the result of [ergo.Fingerprint].
+	Fingerprint string
+	// Exceptions are the errors in the chain from the outermost one.
+	// The errors joined by [errors.Join] are walked in depth-first order.
+	Exceptions []*Exception
+}
+
+// Exception is an error in the chain of an error.
+type Exception struct {
+	// Type is the Go type of the error such as "*fs.PathError".
+	// The type of the errors created by the ergo package is [ErgoType].
+	Type string
+	// Message is the message of the error without the message of its parent.
+	// The message of an error which joins errors such as [errors.Join] is empty
+	// if it is only the messages of the joined errors.
+	Message string
+	// Code is the code of the error which is obtained by [ergo.CodeOf].
+	Code ergo.Code
+	// Attrs are the attributes of the error which are not the ones of its parent.
+	Attrs []slog.Attr
+	// Frames are the stack trace recorded by the error from the innermost call.
+	// The filters set by [ergo.SetFrameFilter] are applied.
+	Frames []*Frame
+}
+
+// Frame is a stack frame.
+type Frame struct {
+	// Function is the function name without the package path such as "F.func1".
+	Function string
+	// Package is the import path of the function.
+	Package string
+	File    string
+	Line    int
+}
+
+// FromError builds an event from the error.
+// The errors which only attach a code by [ergo.WithCode] and
+// the errors which have no message, no attributes and no stack trace are omitted from the exceptions.
+// If err is nil, FromError returns nil.
+func FromError(err error) *Event {
+	if err == nil {
+		return nil
+	}
+
+	event := &Event{
+		Message:     err.Error(),
+		Code:        ergo.CodeOf(err),
+		Attrs:       slices.Collect(ergo.AttrsAll(err)),
+		Fingerprint: ergo.Fingerprint(err),
+	}
+
+	event.Exceptions = appendExceptions(nil, err)
+
+	return event
+}
+
+// appendExceptions appends the exceptions of the error and its parents in depth-first order.
+func appendExceptions(exceptions []*Exception, err error) []*Exception {
+	parents := parentsOf(err)
+	if exception := newException(err, parents); exception != nil {
+		exceptions = append(exceptions, exception)
+	}
+
+	for _, parent := range parents {
+		exceptions = appendExceptions(exceptions, parent)
+	}
+
+	return exceptions
+}
+
+// parentsOf returns the errors which are wrapped by the error.
+func parentsOf(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return slices.DeleteFunc(slices.Clone(joined.Unwrap()), func(err error) bool {
+			return err == nil
+		})
+	}
+
+	if parent := errors.Unwrap(err); parent != nil {
+		return []error{parent}
+	}
+
+	return nil
+}
+
+func newException(err error, parents []error) *Exception {
+	if len(parents) == 1 && ergo.CodeOf(err) != ergo.CodeOf(parents[0]) {
+		// the error only attaches a code
+		return nil
+	}
+
+	exception := &Exception{
+		Type:    typeOf(err),
+		Message: ownMessage(err, parents),
+		Code:    ergo.CodeOf(err),
+		Attrs:   ownAttrs(err, parents),
+	}
+
+	if ownStackTrace(err, parents) {
+		for _, frame := range ergo.StackTraceOf(err) {
+			exception.Frames = append(exception.Frames, &Frame{
+				Function: frame.FuncName(),
+				Package:  frame.PkgPath(),
+				File:     frame.File(),
+				Line:     frame.Line(),
+			})
+		}
+	}
+
+	if exception.Message == "" && len(exception.Attrs) == 0 && len(exception.Frames) == 0 {
+		return nil
+	}
+
+	return exception
+}
+
+// typeOf returns the Go type of the error or [ErgoType] if the error is created by the ergo package.
+func typeOf(err error) string {
+	typ := reflect.TypeOf(err)
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.PkgPath() == ergoPkgPath {
+		return ErgoType
+	}
+
+	return fmt.Sprintf("%T", err)
+}
+
+// ownMessage returns the message of the error without the messages of its parents.
+func ownMessage(err error, parents []error) string {
+	msg := err.Error()
+	switch len(parents) {
+	case 0:
+		return msg
+	case 1:
+		return strings.TrimSuffix(strings.TrimSuffix(msg, parents[0].Error()), ": ")
+	}
+
+	msgs := make([]string, len(parents))
+	for i, parent := range parents {
+		msgs[i] = parent.Error()
+	}
+
+	if msg == strings.Join(msgs, "\n") {
+		// errors.Join
+		return ""
+	}
+
+	return msg
+}
+
+// ownAttrs returns the attributes of the error which are not the ones of its parents.
+func ownAttrs(err error, parents []error) []slog.Attr {
+	var parentAttrs []slog.Attr
+	for _, parent := range parents {
+		parentAttrs = slices.AppendSeq(parentAttrs, ergo.AttrsAll(parent))
+	}
+
+	var attrs []slog.Attr
+	for attr := range ergo.AttrsAll(err) {
+		if !slices.ContainsFunc(parentAttrs, attr.Equal) {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	return attrs
+}
+
+// ownStackTrace reports whether the stack trace of the error is recorded by itself not by its parents.
+func ownStackTrace(err error, parents []error) bool {
+	st := ergo.RawStackTraceOf(err)
+	if len(st) == 0 {
+		return false
+	}
+
+	for _, parent := range parents {
+		parentStackTrace := ergo.RawStackTraceOf(parent)
+		if len(parentStackTrace) > 0 && &st[0] == &parentStackTrace[0] {
+			return false
+		}
+	}
+
+	return true
+}
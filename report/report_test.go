@@ -0,0 +1,132 @@
+package report_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/newmo-oss/ergo"
+	"github.com/newmo-oss/ergo/report"
+)
+
+var codeA = ergo.NewCode("A", "code A message")
+
+func newError() error {
+	err := ergo.New("not found", slog.String("id", "id1"))
+	err = ergo.WithCode(err, codeA)
+	return ergo.Wrap(err, "failed to find", slog.Int("retry", 1))
+}
+
+// exception is a comparable summary of report.Exception.
+type exception struct {
+	Type     string
+	Message  string
+	Code     string
+	Attrs    []slog.Attr
+	Function string // the innermost function
+}
+
+func TestFromError(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		err  error
+		want []exception
+	}{
+		"ergo": {newError(), []exception{
+			{report.ErgoType, "failed to find", codeA.String(), []slog.Attr{slog.Int("retry", 1)}, ""},
+			{report.ErgoType, "not found", "", []slog.Attr{slog.String("id", "id1")}, "newError"},
+		}},
+		"empty message": {ergo.Wrap(ergo.NewSentinel("sentinel"), ""), []exception{
+			{report.ErgoType, "", "", nil, "TestFromError"},
+			{"*errors.errorString", "sentinel", "", nil, ""},
+		}},
+		"omitted": {ergo.Wrap(newError(), ""), []exception{
+			{report.ErgoType, "failed to find", codeA.String(), []slog.Attr{slog.Int("retry", 1)}, ""},
+			{report.ErgoType, "not found", "", []slog.Attr{slog.String("id", "id1")}, "newError"},
+		}},
+		"fmt.Errorf": {fmt.Errorf("failed: %w", errors.New("not found")), []exception{
+			{"*fmt.wrapError", "failed", "", nil, ""},
+			{"*errors.errorString", "not found", "", nil, ""},
+		}},
+		"not suffix": {fmt.Errorf("%w: failed", errors.New("not found")), []exception{
+			{"*fmt.wrapError", "not found: failed", "", nil, ""},
+			{"*errors.errorString", "not found", "", nil, ""},
+		}},
+		"joined": {ergo.Wrap(errors.Join(newError(), errors.New("timeout")), "failed to find all"), []exception{
+			{report.ErgoType, "failed to find all", codeA.String(), nil, ""},
+			{report.ErgoType, "failed to find", codeA.String(), []slog.Attr{slog.Int("retry", 1)}, ""},
+			{report.ErgoType, "not found", "", []slog.Attr{slog.String("id", "id1")}, "newError"},
+			{"*errors.errorString", "timeout", "", nil, ""},
+		}},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			event := report.FromError(tt.err)
+
+			if got, want := event.Message, tt.err.Error(); got != want {
+				t.Errorf("Message does not match: (got, want) = (%q, %q)", got, want)
+			}
+
+			if got, want := event.Code, ergo.CodeOf(tt.err); got != want {
+				t.Errorf("Code does not match: (got, want) = (%v, %v)", got, want)
+			}
+
+			if got, want := event.Fingerprint, ergo.Fingerprint(tt.err); got != want {
+				t.Errorf("Fingerprint does not match: (got, want) = (%q, %q)", got, want)
+			}
+
+			got := make([]exception, len(event.Exceptions))
+			for i, e := range event.Exceptions {
+				got[i] = exception{Type: e.Type, Message: e.Message, Attrs: e.Attrs}
+				if !e.Code.IsZero() {
+					got[i].Code = e.Code.String()
+				}
+				if len(e.Frames) > 0 {
+					got[i].Function = e.Frames[0].Function
+					if pkg := e.Frames[0].Package; pkg != "github.com/newmo-oss/ergo/report_test" {
+						t.Errorf("Package of the frame does not match: %q", pkg)
+					}
+				}
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Exceptions do not match (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if got := report.FromError(nil); got != nil {
+		t.Errorf("FromError(nil) must be nil but got %v", got)
+	}
+}
+
+func TestMemoryReporter(t *testing.T) {
+	t.Parallel()
+
+	var r report.MemoryReporter
+
+	events := []*report.Event{report.FromError(newError()), report.FromError(ergo.New("error"))}
+	for _, event := range events {
+		if err := r.Report(context.Background(), event); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	got := r.Events()
+	if len(got) != len(events) || got[0] != events[0] || got[1] != events[1] {
+		t.Errorf("Events does not match: (got, want) = (%v, %v)", got, events)
+	}
+
+	r.Reset()
+	if got := r.Events(); len(got) != 0 {
+		t.Errorf("Events must be empty after Reset but got %v", got)
+	}
+}
@@ -0,0 +1,109 @@
+package ergo_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/newmo-oss/ergo"
+)
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	notFound := ergo.WithCode(ergo.New("not found", slog.String("id", "u1"), slog.Int("user_id", 100)), codeA)
+
+	cases := map[string]struct {
+		err  error
+		opts []ergo.ReportOption
+
+		want string
+	}{
+		"nil": {nil, nil, ""},
+		"chain": {ergo.Wrap(notFound, "failed to find", slog.Int("retry", 1)), nil, `
+failed to find
+│  retry: 1
+└─ not found [github.com/newmo-oss/ergo_test.A]
+      id     : u1
+      user_id: 100
+`},
+		"empty message": {ergo.Wrap(ergo.Wrap(notFound, ""), "failed"), nil, `
+failed
+└─ not found [github.com/newmo-oss/ergo_test.A]
+      id     : u1
+      user_id: 100
+`},
+		"only attrs": {ergo.Wrap(ergo.NewSentinel("sentinel"), "", slog.Int("n", 1)), nil, `
+(no message)
+│  n: 1
+└─ sentinel
+`},
+		"fmt.Errorf": {fmt.Errorf("failed: %w", errors.New("not found")), nil, `
+failed
+└─ not found
+`},
+		"join": {ergo.Wrap(errors.Join(ergo.New("error1", slog.Int("n", 1)), fmt.Errorf("error2: %w", errors.New("error3"))), "failed"), nil, `
+failed
+└─ (joined errors)
+   ├─ error1
+   │     n: 1
+   └─ error2
+      └─ error3
+`},
+		"nil in multiple errors": {multiError{ergo.New("error1"), nil, ergo.New("error2")}, nil, `
+(joined errors)
+├─ error1
+└─ error2
+`},
+		"color": {ergo.Wrap(notFound, "failed to find"), options(ergo.ReportColor(true)), `
+` + "\x1b[1mfailed to find\x1b[0m" + `
+└─ ` + "\x1b[1mnot found\x1b[0m \x1b[33m[github.com/newmo-oss/ergo_test.A]\x1b[0m" + `
+      ` + "\x1b[36mid     \x1b[0m" + `: u1
+      ` + "\x1b[36muser_id\x1b[0m" + `: 100
+`},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := ergo.Report(&buf, tt.err, tt.opts...); err != nil {
+				t.Fatal("unexpected error:", err)
+			}
+
+			if diff := cmp.Diff(strings.TrimPrefix(tt.want, "\n"), buf.String()); diff != "" {
+				t.Errorf("Report does not match (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReportStack(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := ergo.Report(&buf, newErrorForTest("error"), ergo.ReportStack(true)); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	want := "error\n\nstack:\n  github.com/newmo-oss/ergo_test.newErrorForTest.func1\n      "
+	if got := buf.String(); !strings.HasPrefix(got, want) {
+		t.Errorf("Report must start with %q but got %q", want, got)
+	}
+}
+
+type multiError []error
+
+func (errs multiError) Error() string {
+	return "error1\nerror2"
+}
+
+func (errs multiError) Unwrap() []error {
+	return errs
+}
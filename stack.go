@@ -0,0 +1,167 @@
+package ergo
+
+import (
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/newmo-oss/go-caller"
+)
+
+// StackCapturer captures a stack trace of the callers for [New] and [Wrap].
+// The argument skip is the number of stack frames to skip before recording,
+// with 0 identifying the caller of the StackCapturer as same as [caller.New].
+// A StackCapturer which calls [caller.New] must add 1 to skip for the frame of itself.
+type StackCapturer func(skip int) caller.StackTrace
+
+var stackCapturer atomic.Pointer[StackCapturer]
+
+// SetStackCapturer replaces the StackCapturer which is used by [New] and [Wrap]
+// and returns a function which restores the previous one.
+// If capturer is nil, the default one which calls [caller.New] is used.
+//
+// SetStackCapturer is intended for tests which assert stack traces
+// without depending on line numbers such as:
+//
+//	restore := ergo.SetStackCapturer(ergo.PackageStackCapturer("example.com/repo"))
+//	t.Cleanup(restore)
+//
+// Because the StackCapturer is shared by all goroutines,
+// such tests must not run in parallel with other tests which create errors.
+func SetStackCapturer(capturer StackCapturer) (restore func()) {
+	var p *StackCapturer
+	if capturer != nil {
+		p = &capturer
+	}
+
+	prev := stackCapturer.Swap(p)
+	return func() {
+		stackCapturer.Store(prev)
+	}
+}
+
+// PackageStackCapturer returns a StackCapturer which records only the frames of the packages.
+// A package matches a prefix if its import path is equal to the prefix or under the prefix.
+func PackageStackCapturer(prefixes ...string) StackCapturer {
+	return func(skip int) caller.StackTrace {
+		var st caller.StackTrace
+		for _, frame := range caller.New(skip + 1) {
+			if hasPackagePrefix(frame.PkgPath(), prefixes...) {
+				st = append(st, frame)
+			}
+		}
+		return st
+	}
+}
+
+func hasPackagePrefix(pkgpath string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if pkgpath == prefix || strings.HasPrefix(pkgpath, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// FrameFilter reports whether the frame is kept in stack traces returned by [StackTraceOf].
+type FrameFilter func(frame caller.Frame) bool
+
+var (
+	frameFilters  atomic.Pointer[[]FrameFilter]
+	maxStackDepth atomic.Int64
+)
+
+// SetFrameFilter sets the filters which are applied to stack traces returned by [StackTraceOf]
+// and returns a function which restores the previous ones.
+// A frame is kept only if all the filters keep it.
+// Calling SetFrameFilter without filters removes the filters.
+// The filters do not change stack traces recorded in errors,
+// thus [RawStackTraceOf] still returns all the frames.
+//
+//	ergo.SetFrameFilter(ergo.DropRuntime, ergo.DropStdlib)
+func SetFrameFilter(filters ...FrameFilter) (restore func()) {
+	var p *[]FrameFilter
+	if len(filters) > 0 {
+		filters = slices.Clone(filters)
+		p = &filters
+	}
+
+	prev := frameFilters.Swap(p)
+	return func() {
+		frameFilters.Store(prev)
+	}
+}
+
+// SetMaxStackDepth sets the maximum number of frames in stack traces returned by [StackTraceOf]
+// and returns a function which restores the previous one.
+// The frames are counted after the filters set by [SetFrameFilter] are applied.
+// If depth is 0 or less, the number of frames is not limited.
+func SetMaxStackDepth(depth int) (restore func()) {
+	prev := maxStackDepth.Swap(int64(max(depth, 0)))
+	return func() {
+		maxStackDepth.Store(prev)
+	}
+}
+
+// DropRuntime is a [FrameFilter] which drops frames of the runtime package and its sub packages.
+func DropRuntime(frame caller.Frame) bool {
+	return !hasPackagePrefix(frame.PkgPath(), "runtime")
+}
+
+// DropStdlib is a [FrameFilter] which drops frames of the standard library such as runtime, testing and net/http.
+// A package is treated as a part of the standard library if the first element of its import path does not have a dot.
+func DropStdlib(frame caller.Frame) bool {
+	pkgpath := frame.PkgPath()
+	if pkgpath == "main" {
+		return true
+	}
+
+	elem, _, _ := strings.Cut(pkgpath, "/")
+	return strings.Contains(elem, ".")
+}
+
+// KeepModule returns a [FrameFilter] which keeps only frames of the packages under the prefix
+// such as a module path.
+func KeepModule(prefix string) FrameFilter {
+	return func(frame caller.Frame) bool {
+		return hasPackagePrefix(frame.PkgPath(), prefix)
+	}
+}
+
+// filterStack applies the filters set by [SetFrameFilter] and the maximum depth set by [SetMaxStackDepth].
+func filterStack(st caller.StackTrace) caller.StackTrace {
+	var filters []FrameFilter
+	if p := frameFilters.Load(); p != nil {
+		filters = *p
+	}
+	depth := int(maxStackDepth.Load())
+
+	if st == nil || (len(filters) == 0 && depth == 0) {
+		return st
+	}
+
+	filtered := make(caller.StackTrace, 0, len(st))
+	for _, frame := range st {
+		if depth > 0 && len(filtered) >= depth {
+			break
+		}
+
+		if !slices.ContainsFunc(filters, func(filter FrameFilter) bool { return !filter(frame) }) {
+			filtered = append(filtered, frame)
+		}
+	}
+
+	if len(filtered) == 0 {
+		// all the frames are filtered out
+		return nil
+	}
+
+	return filtered
+}
+
+func captureStack(skip int) caller.StackTrace {
+	if capturer := stackCapturer.Load(); capturer != nil {
+		return (*capturer)(skip + 1)
+	}
+	return caller.New(skip + 1)
+}
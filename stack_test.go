@@ -0,0 +1,140 @@
+package ergo_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/newmo-oss/go-caller"
+
+	"github.com/newmo-oss/ergo"
+)
+
+// TestSetStackCapturer must not run in parallel because the StackCapturer is shared.
+func TestSetStackCapturer(t *testing.T) {
+	fixed := caller.New(0)
+
+	// the functions from the caller of ergo.New in the test package
+	inTestPkg := []string{"newErrorForTest.func1", "do", "newErrorForTest", "TestSetStackCapturer"}
+
+	cases := map[string]struct {
+		capturer ergo.StackCapturer
+
+		wantFuncs []string // the function names of the frames
+		prefix    bool     // if true, only the first frames are compared because the others depend on the test runner
+	}{
+		"default": {nil, inTestPkg, true},
+		"fixed":   {func(int) caller.StackTrace { return fixed }, funcNames(fixed), false},
+		"skip":    {func(skip int) caller.StackTrace { return caller.New(skip + 1) }, inTestPkg, true},
+		"package": {ergo.PackageStackCapturer("github.com/newmo-oss/ergo_test"), inTestPkg, false},
+		"none":    {ergo.PackageStackCapturer("example.com"), nil, false},
+	}
+
+	for name, tt := range cases {
+		restore := ergo.SetStackCapturer(tt.capturer)
+		st := ergo.StackTraceOf(newErrorForTest("error"))
+		restore()
+
+		got := funcNames(st)
+		if tt.prefix && len(got) > len(tt.wantFuncs) {
+			got = got[:len(tt.wantFuncs)]
+		}
+
+		if diff := cmp.Diff(tt.wantFuncs, got); diff != "" {
+			t.Errorf("%s: the functions of StackTrace do not match (-want +got):\n%s", name, diff)
+		}
+	}
+
+	// the default one is restored
+	got := funcNames(ergo.StackTraceOf(newErrorForTest("error")))
+	if len(got) > len(inTestPkg) {
+		got = got[:len(inTestPkg)]
+	}
+
+	if diff := cmp.Diff(inTestPkg, got); diff != "" {
+		t.Errorf("StackTrace is not restored (-want +got):\n%s", diff)
+	}
+}
+
+// TestSetFrameFilter must not run in parallel because the filters are shared.
+func TestSetFrameFilter(t *testing.T) {
+	err := newErrorForTest("error")
+	raw := ergo.RawStackTraceOf(err)
+
+	inTestPkg := func(f caller.Frame) bool { return f.PkgPath() == "github.com/newmo-oss/ergo_test" }
+
+	cases := map[string]struct {
+		filters []ergo.FrameFilter
+		depth   int
+
+		want caller.StackTrace
+	}{
+		"no filters":     {nil, 0, raw},
+		"DropRuntime":    {[]ergo.FrameFilter{ergo.DropRuntime}, 0, filterFrames(raw, func(f caller.Frame) bool { return f.PkgPath() != "runtime" })},
+		"DropStdlib":     {[]ergo.FrameFilter{ergo.DropStdlib}, 0, filterFrames(raw, inTestPkg)},
+		"KeepModule":     {[]ergo.FrameFilter{ergo.KeepModule("github.com/newmo-oss/ergo_test")}, 0, filterFrames(raw, inTestPkg)},
+		"other module":   {[]ergo.FrameFilter{ergo.KeepModule("github.com/newmo-oss/ergo")}, 0, nil},
+		"drop all":       {[]ergo.FrameFilter{func(caller.Frame) bool { return false }}, 0, nil},
+		"max depth":      {nil, 2, raw[:2]},
+		"filter & depth": {[]ergo.FrameFilter{ergo.DropStdlib}, 1, raw[:1]},
+		"multi filters":  {[]ergo.FrameFilter{ergo.DropRuntime, ergo.KeepModule("github.com/newmo-oss/ergo_test")}, 0, filterFrames(raw, inTestPkg)},
+	}
+
+	for name, tt := range cases {
+		restoreFilter := ergo.SetFrameFilter(tt.filters...)
+		restoreDepth := ergo.SetMaxStackDepth(tt.depth)
+		got := ergo.StackTraceOf(err)
+		rawGot := ergo.RawStackTraceOf(err)
+		restoreDepth()
+		restoreFilter()
+
+		if got, want := fmt.Sprintf("%+s:%d", got, got), fmt.Sprintf("%+s:%d", tt.want, tt.want); got != want {
+			t.Errorf("%s: StackTraceOf does not match: (got, want) = (%q, %q)", name, got, want)
+		}
+
+		if tt.want == nil && got != nil {
+			t.Errorf("%s: StackTraceOf must be nil but got %v", name, got)
+		}
+
+		if got, want := fmt.Sprintf("%v", rawGot), fmt.Sprintf("%v", raw); got != want {
+			t.Errorf("%s: RawStackTraceOf does not match: (got, want) = (%q, %q)", name, got, want)
+		}
+	}
+
+	// the filters are restored
+	if got, want := len(ergo.StackTraceOf(err)), len(raw); got != want {
+		t.Errorf("the number of frames does not match: (got, want) = (%d, %d)", got, want)
+	}
+}
+
+func TestDropStdlib(t *testing.T) {
+	t.Parallel()
+
+	// the frames of the test package, testing and runtime
+	st := caller.New(0)
+
+	for _, f := range st {
+		want := f.PkgPath() == "github.com/newmo-oss/ergo_test"
+		if got := ergo.DropStdlib(f); got != want {
+			t.Errorf("DropStdlib(%s) does not match: (got, want) = (%v, %v)", f.RuntimeFrame().Function, got, want)
+		}
+	}
+}
+
+func filterFrames(st caller.StackTrace, keep func(caller.Frame) bool) caller.StackTrace {
+	filtered := caller.StackTrace{}
+	for _, f := range st {
+		if keep(f) {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+func funcNames(st caller.StackTrace) []string {
+	var names []string
+	for _, f := range st {
+		names = append(names, f.FuncName())
+	}
+	return names
+}